@@ -0,0 +1,179 @@
+package ComlinkGo
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+const defaultBulkWorkers = 10
+
+type PlayersOptions struct {
+	// Workers is how many /player requests run at once. Defaults to 10.
+	Workers           int
+	Enums             bool
+	PlayerDetailsOnly bool
+}
+
+type PlayerResult struct {
+	// ID is the ally code or player id exactly as it was passed to Players.
+	ID     string
+	Player *PlayerResponse
+	Err    error
+}
+
+// Players fetches every ally code or player id with a bounded number of workers. Results are sent
+// in the order they finish and the channel is closed once every id has a result. A failed player
+// does not stop the rest of the batch.
+func (c *Comlink) Players(ctx context.Context, allyCodesOrIds []string, opts *PlayersOptions) <-chan PlayerResult {
+	if opts == nil {
+		opts = &PlayersOptions{}
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultBulkWorkers
+	}
+
+	workers = min(workers, max(len(allyCodesOrIds), 1))
+
+	ctx, cancel := c.HttpClient.WithClientContext(ctx)
+
+	ids := make(chan string)
+	results := make(chan PlayerResult, len(allyCodesOrIds))
+
+	var workerWg sync.WaitGroup
+
+	for range workers {
+		workerWg.Add(1)
+		c.Wg.Add(1)
+
+		go func() {
+			defer c.Wg.Done()
+			defer workerWg.Done()
+
+			for id := range ids {
+				player, err := c.PlayerTypedCtx(ctx, playerRequestBody(id, opts))
+				results <- PlayerResult{ID: id, Player: player, Err: err}
+			}
+		}()
+	}
+
+	c.Wg.Add(1)
+
+	go func() {
+		defer c.Wg.Done()
+
+	feed:
+		for i, id := range allyCodesOrIds {
+			select {
+			case ids <- id:
+			case <-ctx.Done():
+				for _, skipped := range allyCodesOrIds[i:] {
+					results <- PlayerResult{ID: skipped, Err: ctx.Err()}
+				}
+
+				break feed
+			}
+		}
+
+		close(ids)
+		workerWg.Wait()
+		close(results)
+		cancel()
+	}()
+
+	return results
+}
+
+// CollectPlayers drains the channel from Players into players and errors keyed by PlayerResult.ID.
+func CollectPlayers(results <-chan PlayerResult) (map[string]*PlayerResponse, map[string]error) {
+	players := make(map[string]*PlayerResponse)
+	errs := make(map[string]error)
+
+	for result := range results {
+		if result.Err != nil {
+			errs[result.ID] = result.Err
+
+			continue
+		}
+
+		players[result.ID] = result.Player
+	}
+
+	return players, errs
+}
+
+func playerRequestBody(allyCodeOrId string, opts *PlayersOptions) RequestBody {
+	payload := RequestBody{
+		Payload: Payload{
+			PlayerDetailsOnly: opts.PlayerDetailsOnly,
+		},
+		Enums: opts.Enums,
+	}
+
+	if allyCode, ok := normalizeAllyCode(allyCodeOrId); ok {
+		payload.Payload.AllyCode = allyCode
+	} else {
+		payload.Payload.PlayerId = allyCodeOrId
+	}
+
+	return payload
+}
+
+// normalizeAllyCode accepts ally codes with or without dashes, such as 123-456-789.
+func normalizeAllyCode(value string) (string, bool) {
+	const allyCodeLength = 9
+
+	allyCode := strings.ReplaceAll(value, "-", "")
+	if len(allyCode) != allyCodeLength {
+		return "", false
+	}
+
+	for _, r := range allyCode {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+
+	return allyCode, true
+}
+
+type GuildRoster struct {
+	Guild *GuildResponse
+	// Players are keyed by playerId.
+	Players map[string]*PlayerResponse
+	// Errors are keyed by playerId for every member that could not be fetched.
+	Errors map[string]error
+}
+
+// GuildWithRosters fetches a guild and then the full profile of every member. Only a failed /guild
+// call returns an error, members that fail are reported in GuildRoster.Errors.
+func (c *Comlink) GuildWithRosters(ctx context.Context, guildId string, opts *PlayersOptions) (*GuildRoster, error) {
+	if opts == nil {
+		opts = &PlayersOptions{}
+	}
+
+	guild, err := c.GuildTypedCtx(ctx, RequestBody{
+		Payload: Payload{
+			GuildId: guildId,
+		},
+		Enums: opts.Enums,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	playerIds := make([]string, 0, len(guild.Guild.Member))
+	for _, member := range guild.Guild.Member {
+		playerIds = append(playerIds, member.PlayerId)
+	}
+
+	players, errs := CollectPlayers(c.Players(ctx, playerIds, opts))
+
+	return &GuildRoster{
+		Guild:   guild,
+		Players: players,
+		Errors:  errs,
+	}, nil
+}
@@ -0,0 +1,221 @@
+// Command enumgen turns a saved /enums response into typed Go constants with String() methods.
+//
+//	go run ./cmd/enumgen -in enums.json -out enums_gen.go
+//
+// Every enum in the input is generated unless -only is given. -only takes a comma separated list
+// of enum names, each optionally renamed with Enum=GoType. Two values that end up with the same
+// constant name are an error rather than a file that does not compile.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type enumValue struct {
+	Name   string
+	Number int
+}
+
+type enumDef struct {
+	Source string
+	GoType string
+	Values []enumValue
+}
+
+func main() {
+	in := flag.String("in", "enums.json", "saved /enums response")
+	out := flag.String("out", "enums_gen.go", "file to write")
+	pkg := flag.String("package", "ComlinkGo", "package name of the generated file")
+	only := flag.String("only", "", "comma separated enums to generate, optionally renamed with Enum=GoType")
+	flag.Parse()
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var raw map[string]map[string]int
+
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		log.Fatalf("parsing %s: %v", *in, err)
+	}
+
+	defs, err := selectEnums(raw, *only)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = checkIdentifiers(defs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	source, err := generate(*pkg, *in, defs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(*out, source, 0o644) //nolint:gosec,mnd
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func selectEnums(raw map[string]map[string]int, only string) ([]enumDef, error) {
+	goTypes := make(map[string]string)
+
+	if only == "" {
+		for name := range raw {
+			goTypes[name] = name
+		}
+	} else {
+		for _, entry := range strings.Split(only, ",") {
+			name, goType, renamed := strings.Cut(strings.TrimSpace(entry), "=")
+			if !renamed {
+				goType = name
+			}
+
+			if _, ok := raw[name]; !ok {
+				return nil, fmt.Errorf("enum %s is not in the input", name)
+			}
+
+			goTypes[name] = goType
+		}
+	}
+
+	defs := make([]enumDef, 0, len(goTypes))
+
+	for name, goType := range goTypes {
+		def := enumDef{Source: name, GoType: goType}
+
+		for valueName, number := range raw[name] {
+			def.Values = append(def.Values, enumValue{Name: valueName, Number: number})
+		}
+
+		slices.SortFunc(def.Values, func(a, b enumValue) int {
+			if a.Number != b.Number {
+				return a.Number - b.Number
+			}
+
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		defs = append(defs, def)
+	}
+
+	slices.SortFunc(defs, func(a, b enumDef) int {
+		return strings.Compare(a.GoType, b.GoType)
+	})
+
+	return defs, nil
+}
+
+// checkIdentifiers fails when two enums or values would generate the same Go identifier, or one that
+// is not a valid identifier, since the generated file would not compile.
+func checkIdentifiers(defs []enumDef) error {
+	owners := make(map[string]string)
+
+	claim := func(ident, owner string) error {
+		if !token.IsIdentifier(ident) {
+			return fmt.Errorf("%s would generate the invalid identifier %q", owner, ident)
+		}
+
+		if other, ok := owners[ident]; ok {
+			return fmt.Errorf("%s and %s would both generate %s", other, owner, ident)
+		}
+
+		owners[ident] = owner
+
+		return nil
+	}
+
+	for _, def := range defs {
+		err := claim(def.GoType, "enum "+def.Source)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, def := range defs {
+		for _, value := range def.Values {
+			err := claim(constName(def, value), def.Source+"."+value.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func generate(pkg string, in string, defs []enumDef) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by enumgen from %s; DO NOT EDIT.\n\npackage %s\n\nimport \"strconv\"\n", in, pkg)
+
+	for _, def := range defs {
+		fmt.Fprintf(&buf, "\ntype %s int\n\nconst (\n", def.GoType)
+
+		for _, value := range def.Values {
+			fmt.Fprintf(&buf, "\t%s %s = %d\n", constName(def, value), def.GoType, value.Number)
+		}
+
+		fmt.Fprintf(&buf, ")\n\nfunc (v %s) String() string {\n\tswitch v {\n", def.GoType)
+
+		seen := make(map[int]bool)
+
+		for _, value := range def.Values {
+			if seen[value.Number] {
+				continue
+			}
+
+			seen[value.Number] = true
+
+			fmt.Fprintf(&buf, "\tcase %s:\n\t\treturn %q\n", constName(def, value), value.Name)
+		}
+
+		fmt.Fprintf(&buf, "\tdefault:\n\t\treturn %q + strconv.Itoa(int(v)) + \")\"\n\t}\n}\n", def.GoType+"(")
+	}
+
+	return format.Source(buf.Bytes()) //nolint:wrapcheck
+}
+
+// constName builds GoType + CamelCase(value), dropping a repeated prefix such as DIVISION_ on Division.
+func constName(def enumDef, value enumValue) string {
+	name := value.Name
+
+	for _, prefix := range []string{def.Source, def.GoType} {
+		trimmed, ok := strings.CutPrefix(strings.ToUpper(name), strings.ToUpper(prefix)+"_")
+		if ok {
+			name = trimmed
+
+			break
+		}
+	}
+
+	var camel strings.Builder
+
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == ' ' || r == '-' }) {
+		lower := []rune(strings.ToLower(part))
+		lower[0] = unicode.ToUpper(lower[0])
+		camel.WriteString(string(lower))
+	}
+
+	if camel.Len() == 0 {
+		return def.GoType + strconv.Itoa(value.Number)
+	}
+
+	return def.GoType + camel.String()
+}
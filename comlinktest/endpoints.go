@@ -0,0 +1,134 @@
+package comlinktest
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+const localizationFile = "Loc_ENG_US.txt"
+
+func mustLoadFixture[T any](name string) T {
+	var fixture T
+
+	data, err := fixtures.ReadFile("fixtures/" + name)
+	if err != nil {
+		panic(fmt.Sprintf("comlinktest: missing fixture %s: %v", name, err))
+	}
+
+	err = json.Unmarshal(data, &fixture)
+	if err != nil {
+		panic(fmt.Sprintf("comlinktest: invalid fixture %s: %v", name, err))
+	}
+
+	return fixture
+}
+
+func (s *Server) serveEndpoint(w http.ResponseWriter, endpoint string, request requestBody) {
+	switch endpoint {
+	case "/enums":
+		writeJSON(w, http.StatusOK, mustLoadFixture[map[string]any]("enums.json"))
+	case "/metadata":
+		writeJSON(w, http.StatusOK, mustLoadFixture[map[string]any]("metadata.json"))
+	case "/data":
+		writeJSON(w, http.StatusOK, mustLoadFixture[map[string]any]("data.json"))
+	case "/getevents":
+		writeJSON(w, http.StatusOK, mustLoadFixture[map[string]any]("events.json"))
+	case "/getleaderboard":
+		writeJSON(w, http.StatusOK, mustLoadFixture[map[string]any]("leaderboard.json"))
+	case "/getguildleaderboard":
+		writeJSON(w, http.StatusOK, mustLoadFixture[map[string]any]("guildleaderboard.json"))
+	case "/localization":
+		s.serveLocalization(w, request)
+	case "/player", "/playerarena":
+		s.servePlayer(w, request)
+	case "/guild":
+		s.serveGuild(w, request)
+	case "/getguilds":
+		s.serveGetGuilds(w)
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint "+endpoint)
+	}
+}
+
+func (s *Server) servePlayer(w http.ResponseWriter, request requestBody) {
+	for _, player := range s.players {
+		if (request.Payload.AllyCode != "" && player["allyCode"] == request.Payload.AllyCode) ||
+			(request.Payload.PlayerId != "" && player["playerId"] == request.Payload.PlayerId) {
+			writeJSON(w, http.StatusOK, player)
+
+			return
+		}
+	}
+
+	writeError(w, http.StatusBadRequest, "player not found")
+}
+
+func (s *Server) serveGuild(w http.ResponseWriter, request requestBody) {
+	for _, guild := range s.guilds {
+		inner, _ := guild["guild"].(map[string]any)
+		profile, _ := inner["profile"].(map[string]any)
+
+		if profile["id"] == request.Payload.GuildId {
+			writeJSON(w, http.StatusOK, guild)
+
+			return
+		}
+	}
+
+	writeError(w, http.StatusBadRequest, "guild not found")
+}
+
+func (s *Server) serveGetGuilds(w http.ResponseWriter) {
+	profiles := make([]any, 0, len(s.guilds))
+
+	for _, guild := range s.guilds {
+		inner, _ := guild["guild"].(map[string]any)
+		profiles = append(profiles, inner["profile"])
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"guild": profiles})
+}
+
+func (s *Server) serveLocalization(w http.ResponseWriter, request requestBody) {
+	contents, err := fixtures.ReadFile("fixtures/" + localizationFile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	if request.Unzip {
+		writeJSON(w, http.StatusOK, map[string]string{localizationFile: string(contents)})
+
+		return
+	}
+
+	var buf bytes.Buffer
+
+	zipWriter := zip.NewWriter(&buf)
+
+	file, err := zipWriter.Create(localizationFile)
+	if err == nil {
+		_, err = file.Write(contents)
+	}
+
+	if err == nil {
+		err = zipWriter.Close()
+	}
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"localizationBundle": base64.StdEncoding.EncodeToString(buf.Bytes())})
+}
@@ -0,0 +1,274 @@
+// Package comlinktest provides an in-memory fake comlink for tests. It implements every endpoint
+// ComlinkGo supports with canned fixtures and can be told to fail in the ways a real comlink does.
+package comlinktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+)
+
+type Failure struct {
+	// StatusCode defaults to 500 when Error or Body is set.
+	StatusCode int
+	Error      *ComlinkGo.ComlinkError
+	// Body is written as is when Error is nil, useful for non-JSON error pages.
+	Body string
+	// Latency is waited before answering, or before dropping the connection.
+	Latency        time.Duration
+	DropConnection bool
+	// Times is how many requests fail before the endpoint recovers. 0 means every request.
+	Times int
+}
+
+type Option func(*Server)
+
+// WithHMAC makes the server reject any request not signed with these keys.
+func WithHMAC(accessKey, secretKey string) Option {
+	return func(s *Server) {
+		s.AccessKey = accessKey
+		s.SecretKey = secretKey
+	}
+}
+
+// WithFixture replaces the canned response for endpoint, such as "/metadata".
+func WithFixture(endpoint string, response any) Option {
+	return func(s *Server) {
+		s.SetFixture(endpoint, response)
+	}
+}
+
+type Server struct {
+	*httptest.Server
+	AccessKey string
+	SecretKey string
+
+	mu       sync.Mutex
+	fixtures map[string]any
+	failures map[string]*Failure
+	requests map[string]int
+	bodies   map[string][]byte
+	players  []map[string]any
+	guilds   []map[string]any
+}
+
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		fixtures: make(map[string]any),
+		failures: make(map[string]*Failure),
+		requests: make(map[string]int),
+		bodies:   make(map[string][]byte),
+		players:  mustLoadFixture[[]map[string]any]("players.json"),
+		guilds:   mustLoadFixture[[]map[string]any]("guilds.json"),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Settings returns ComlinkSettings pointed at this server, signed with its HMAC keys if any.
+func (s *Server) Settings() *ComlinkGo.ComlinkSettings {
+	return &ComlinkGo.ComlinkSettings{
+		ComlinkURL: s.URL,
+		HMAC: ComlinkGo.HMACSettings{
+			AccessKey: s.AccessKey,
+			SecretKey: s.SecretKey,
+		},
+	}
+}
+
+func (s *Server) SetFixture(endpoint string, response any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fixtures[endpointKey(endpoint)] = response
+}
+
+func (s *Server) SetFailure(endpoint string, failure Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[endpointKey(endpoint)] = &failure
+}
+
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = make(map[string]*Failure)
+}
+
+// Requests returns how many requests endpoint has received, including failed ones.
+func (s *Server) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[endpointKey(endpoint)]
+}
+
+// LastBody returns the body of the last request endpoint received, or nil.
+func (s *Server) LastBody(endpoint string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.bodies[endpointKey(endpoint)]
+}
+
+type requestBody struct {
+	Payload struct {
+		AllyCode string `json:"allyCode"`
+		PlayerId string `json:"playerId"`
+		GuildId  string `json:"guildId"`
+		Id       string `json:"id"`
+	} `json:"payload"`
+	Unzip bool `json:"unzip"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := endpointKey(r.URL.Path)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read body")
+
+		return
+	}
+
+	failure, fixture, hasFixture := s.record(endpoint, body)
+
+	if failure != nil && s.fail(w, r, failure) {
+		return
+	}
+
+	if s.AccessKey != "" && r.Method == http.MethodPost {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		err = ComlinkGo.VerifySignature(r, map[string]string{s.AccessKey: s.SecretKey})
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err.Error())
+
+			return
+		}
+	}
+
+	if hasFixture {
+		writeJSON(w, http.StatusOK, fixture)
+
+		return
+	}
+
+	var request requestBody
+
+	if len(body) > 0 {
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+
+			return
+		}
+	}
+
+	s.serveEndpoint(w, endpoint, request)
+}
+
+func (s *Server) record(endpoint string, body []byte) (*Failure, any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[endpoint]++
+	s.bodies[endpoint] = body
+
+	fixture, hasFixture := s.fixtures[endpoint]
+
+	failure := s.failures[endpoint]
+	if failure == nil {
+		return nil, fixture, hasFixture
+	}
+
+	active := *failure
+
+	if failure.Times > 0 {
+		failure.Times--
+		if failure.Times == 0 {
+			delete(s.failures, endpoint)
+		}
+	}
+
+	return &active, fixture, hasFixture
+}
+
+// fail applies failure and reports whether the response has been handled. A failure with only
+// Latency set delays the normal response instead of replacing it.
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, failure *Failure) bool {
+	if failure.Latency > 0 {
+		select {
+		case <-time.After(failure.Latency):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	if failure.DropConnection {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			panic("comlinktest: ResponseWriter does not support hijacking")
+		}
+
+		conn, _, err := hijacker.Hijack()
+		if err == nil {
+			conn.Close()
+		}
+
+		return true
+	}
+
+	statusCode := failure.StatusCode
+	if statusCode == 0 {
+		if failure.Error == nil && failure.Body == "" {
+			return false
+		}
+
+		statusCode = http.StatusInternalServerError
+	}
+
+	if failure.Error != nil {
+		writeJSON(w, statusCode, failure.Error)
+
+		return true
+	}
+
+	w.WriteHeader(statusCode)
+	_, _ = io.WriteString(w, failure.Body)
+
+	return true
+}
+
+func endpointKey(urlPath string) string {
+	return "/" + strings.ToLower(path.Base(urlPath))
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, ComlinkGo.ComlinkError{
+		Code:    http.StatusText(statusCode),
+		Message: message,
+	})
+}
@@ -0,0 +1,138 @@
+package ComlinkGo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var ErrMissingCredentials = errors.New("missing HMAC credentials")
+
+const (
+	DefaultAccessKeyEnv = "COMLINK_ACCESS_KEY"
+	DefaultSecretKeyEnv = "COMLINK_SECRET_KEY"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the Clock used when ComlinkSettings.Clock is nil.
+var SystemClock Clock = systemClock{}
+
+// ClockFunc lets a plain function, such as one returning a fixed time in tests, be used as a Clock.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// CredentialsProvider is asked for HMAC keys on every signed request, so keys can be rotated
+// without rebuilding the Comlink.
+type CredentialsProvider interface {
+	Credentials() (HMACSettings, error)
+}
+
+type StaticCredentials HMACSettings
+
+func (s StaticCredentials) Credentials() (HMACSettings, error) {
+	if s.AccessKey == "" || s.SecretKey == "" {
+		return HMACSettings{}, ErrMissingCredentials
+	}
+
+	return HMACSettings(s), nil
+}
+
+// EnvCredentials reads the keys from the environment on every call. Empty names fall back to
+// DefaultAccessKeyEnv and DefaultSecretKeyEnv.
+type EnvCredentials struct {
+	AccessKeyEnv string
+	SecretKeyEnv string
+}
+
+func (e EnvCredentials) Credentials() (HMACSettings, error) {
+	accessKeyEnv, secretKeyEnv := e.AccessKeyEnv, e.SecretKeyEnv
+	if accessKeyEnv == "" {
+		accessKeyEnv = DefaultAccessKeyEnv
+	}
+
+	if secretKeyEnv == "" {
+		secretKeyEnv = DefaultSecretKeyEnv
+	}
+
+	credentials := StaticCredentials{
+		AccessKey: os.Getenv(accessKeyEnv),
+		SecretKey: os.Getenv(secretKeyEnv),
+	}
+
+	creds, err := credentials.Credentials()
+	if err != nil {
+		return HMACSettings{}, fmt.Errorf("%w: set %s and %s", err, accessKeyEnv, secretKeyEnv)
+	}
+
+	return creds, nil
+}
+
+// FileCredentials reads {"accessKey": "...", "secretKey": "..."} from Path and reloads it whenever
+// the file changes.
+type FileCredentials struct {
+	Path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	cached  HMACSettings
+}
+
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{Path: path}
+}
+
+func (f *FileCredentials) Credentials() (HMACSettings, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return HMACSettings{}, fmt.Errorf("%w: %w", ErrMissingCredentials, err)
+	}
+
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.cached, nil
+	}
+
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return HMACSettings{}, fmt.Errorf("%w: %w", ErrMissingCredentials, err)
+	}
+
+	var file struct {
+		AccessKey string `json:"accessKey"`
+		SecretKey string `json:"secretKey"`
+	}
+
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return HMACSettings{}, fmt.Errorf("%w: %w", ErrMissingCredentials, err)
+	}
+
+	creds, err := StaticCredentials(file).Credentials()
+	if err != nil {
+		return HMACSettings{}, fmt.Errorf("%w: %s", err, f.Path)
+	}
+
+	f.cached = creds
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+
+	return creds, nil
+}
@@ -0,0 +1,387 @@
+package ComlinkGo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var ErrInvalidRequest = errors.New("invalid request")
+
+// Request is implemented by every per-endpoint request type. Send validates it before any HTTP call
+// and turns it into a Body for the endpoint. Fields the endpoint needs are always sent, even when
+// they hold a zero value such as StartIndex 0.
+type Request interface {
+	Endpoint() string
+	Validate() error
+	RequestBody() Body
+}
+
+func (c *Comlink) Send(ctx context.Context, req Request) (map[string]any, error) {
+	return handleResp(c.SendRaw(ctx, req)) //nolint:bodyclose // Handled by handleResp()
+}
+
+// SendRaw is Send without decoding. Use DecodeResponse to decode into a typed response.
+func (c *Comlink) SendRaw(ctx context.Context, req Request) (*http.Response, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.post(ctx, req.Endpoint(), req.RequestBody())
+}
+
+// requestBody converts body the same way every RequestBody is converted, then lets required set the
+// fields that have to be sent even when they are zero. Enums is always sent.
+func requestBody(body RequestBody, required func(body *RequestBodyPointer)) RequestBodyPointer {
+	converted := convertRequestBody(body)
+	converted.Enums = Ptr(body.Enums)
+
+	if required != nil {
+		if converted.Payload == nil {
+			converted.Payload = &PayloadPointer{}
+		}
+
+		required(&converted)
+	}
+
+	return converted
+}
+
+func invalidRequest(endpoint string, format string, args ...any) error {
+	return fmt.Errorf("%w: %s: %s", ErrInvalidRequest, endpoint, fmt.Sprintf(format, args...))
+}
+
+type PlayerRequest struct {
+	// Exactly one of AllyCode or PlayerId must be set. AllyCode may contain dashes.
+	AllyCode          string
+	PlayerId          string
+	PlayerDetailsOnly bool
+	Enums             bool
+}
+
+func (r PlayerRequest) Endpoint() string {
+	return "/player"
+}
+
+func (r PlayerRequest) Validate() error {
+	return validatePlayerIdentity(r.Endpoint(), r.AllyCode, r.PlayerId)
+}
+
+func (r PlayerRequest) RequestBody() Body {
+	allyCode, _ := normalizeAllyCode(r.AllyCode)
+
+	return requestBody(RequestBody{
+		Payload: Payload{AllyCode: allyCode, PlayerId: r.PlayerId},
+		Enums:   r.Enums,
+	}, func(body *RequestBodyPointer) {
+		body.Payload.PlayerDetailsOnly = Ptr(r.PlayerDetailsOnly)
+	})
+}
+
+type PlayerArenaRequest struct {
+	// Exactly one of AllyCode or PlayerId must be set. AllyCode may contain dashes.
+	AllyCode          string
+	PlayerId          string
+	PlayerDetailsOnly bool
+	Enums             bool
+}
+
+func (r PlayerArenaRequest) Endpoint() string {
+	return "/playerArena"
+}
+
+func (r PlayerArenaRequest) Validate() error {
+	return validatePlayerIdentity(r.Endpoint(), r.AllyCode, r.PlayerId)
+}
+
+func (r PlayerArenaRequest) RequestBody() Body {
+	return PlayerRequest(r).RequestBody()
+}
+
+func validatePlayerIdentity(endpoint, allyCode, playerId string) error {
+	switch {
+	case allyCode == "" && playerId == "":
+		return invalidRequest(endpoint, "AllyCode or PlayerId is required")
+	case allyCode != "" && playerId != "":
+		return invalidRequest(endpoint, "only one of AllyCode or PlayerId can be set")
+	case allyCode != "":
+		if _, ok := normalizeAllyCode(allyCode); !ok {
+			return invalidRequest(endpoint, "AllyCode %q is not 9 digits", allyCode)
+		}
+	}
+
+	return nil
+}
+
+type GuildRequest struct {
+	GuildId                        string
+	IncludeRecentGuildActivityInfo bool
+	Enums                          bool
+}
+
+func (r GuildRequest) Endpoint() string {
+	return "/Guild"
+}
+
+func (r GuildRequest) Validate() error {
+	if r.GuildId == "" {
+		return invalidRequest(r.Endpoint(), "GuildId is required")
+	}
+
+	return nil
+}
+
+func (r GuildRequest) RequestBody() Body {
+	return requestBody(RequestBody{
+		Payload: Payload{GuildId: r.GuildId},
+		Enums:   r.Enums,
+	}, func(body *RequestBodyPointer) {
+		body.Payload.IncludeRecentGuildActivityInfo = Ptr(r.IncludeRecentGuildActivityInfo)
+	})
+}
+
+// GetGuildsRequest searches by Name when it is set and by SearchCriteria otherwise.
+type GetGuildsRequest struct {
+	Name           string
+	SearchCriteria SearchCriteria
+	StartIndex     int
+	Count          int
+	Enums          bool
+}
+
+func (r GetGuildsRequest) Endpoint() string {
+	return "/getGuilds"
+}
+
+func (r GetGuildsRequest) Validate() error {
+	if r.Name != "" && !isZeroSearchCriteria(r.SearchCriteria) {
+		return invalidRequest(r.Endpoint(), "Name and SearchCriteria can not both be set")
+	}
+
+	if r.Count < 0 || r.StartIndex < 0 {
+		return invalidRequest(r.Endpoint(), "Count and StartIndex can not be negative")
+	}
+
+	return nil
+}
+
+func (r GetGuildsRequest) RequestBody() Body {
+	payload := Payload{Count: r.Count, Name: r.Name}
+	if r.Name == "" {
+		payload.SearchCriteria = r.SearchCriteria
+	}
+
+	return requestBody(RequestBody{Payload: payload, Enums: r.Enums}, func(body *RequestBodyPointer) {
+		body.Payload.StartIndex = Ptr(r.StartIndex)
+
+		if r.Name != "" {
+			body.Payload.FilterType = Ptr(FilterTypeName)
+
+			return
+		}
+
+		body.Payload.FilterType = Ptr(FilterTypeSearchCriteria)
+
+		if body.Payload.SearchCriteria == nil {
+			body.Payload.SearchCriteria = &SearchCriteriaPointer{}
+		}
+
+		body.Payload.SearchCriteria.IncludeInviteOnly = Ptr(r.SearchCriteria.IncludeInviteOnly)
+	})
+}
+
+func isZeroSearchCriteria(criteria SearchCriteria) bool {
+	return criteria.MinMemberCount == 0 &&
+		criteria.MaxMemberCount == 0 &&
+		!criteria.IncludeInviteOnly &&
+		criteria.MinGuildGalacticPower == 0 &&
+		criteria.MaxGuildGalacticPower == 0 &&
+		len(criteria.RecentTbParticipatedIn) == 0
+}
+
+type GuildLeaderboardRequest struct {
+	// LeaderboardId.LeaderboardType is required, since its zero value GuildLeaderboardTypeAllTimeRaid
+	// is a real leaderboard. A nil MonthOffset is sent as 0.
+	LeaderboardId LeaderboardIdPointer
+	Count         int
+	Enums         bool
+}
+
+func (r GuildLeaderboardRequest) Endpoint() string {
+	return "/getGuildLeaderboard"
+}
+
+func (r GuildLeaderboardRequest) Validate() error {
+	if r.Count < 0 {
+		return invalidRequest(r.Endpoint(), "Count can not be negative")
+	}
+
+	if r.LeaderboardId.LeaderboardType == nil {
+		return invalidRequest(r.Endpoint(), "LeaderboardId.LeaderboardType is required")
+	}
+
+	if r.LeaderboardId.MonthOffset != nil && *r.LeaderboardId.MonthOffset < 0 {
+		return invalidRequest(r.Endpoint(), "MonthOffset can not be negative")
+	}
+
+	return nil
+}
+
+func (r GuildLeaderboardRequest) RequestBody() Body {
+	return requestBody(RequestBody{
+		Payload: Payload{Count: r.Count},
+		Enums:   r.Enums,
+	}, func(body *RequestBodyPointer) {
+		body.Payload.LeaderboardId = &LeaderboardIdPointer{
+			LeaderboardType: r.LeaderboardId.LeaderboardType,
+			MonthOffset:     Ptr(deref(r.LeaderboardId.MonthOffset)),
+		}
+	})
+}
+
+// LeaderboardRequest needs EventInstanceId and GroupId for LeaderboardTypeEventInstance, and
+// League and Division for LeaderboardTypeLeagueDivision.
+type LeaderboardRequest struct {
+	LeaderboardType LeaderboardType
+	EventInstanceId string
+	GroupId         string
+	League          League
+	Division        Division
+	Enums           bool
+}
+
+func (r LeaderboardRequest) Endpoint() string {
+	return "/getLeaderboard"
+}
+
+func (r LeaderboardRequest) Validate() error {
+	switch r.LeaderboardType {
+	case LeaderboardTypeEventInstance:
+		if r.EventInstanceId == "" || r.GroupId == "" {
+			return invalidRequest(r.Endpoint(), "EventInstanceId and GroupId are required for %s", r.LeaderboardType)
+		}
+	case LeaderboardTypeLeagueDivision:
+		if r.League == 0 || r.Division == 0 {
+			return invalidRequest(r.Endpoint(), "League and Division are required for %s", r.LeaderboardType)
+		}
+	default:
+		return invalidRequest(r.Endpoint(), "unknown LeaderboardType %s", r.LeaderboardType)
+	}
+
+	return nil
+}
+
+func (r LeaderboardRequest) RequestBody() Body {
+	return requestBody(RequestBody{
+		Payload: Payload{
+			EventInstanceId: r.EventInstanceId,
+			GroupId:         r.GroupId,
+			League:          r.League,
+			Division:        r.Division,
+		},
+		Enums: r.Enums,
+	}, func(body *RequestBodyPointer) {
+		body.Payload.LeaderboardType = Ptr(r.LeaderboardType)
+	})
+}
+
+type GameDataRequest struct {
+	Version         string
+	IncludePveUnits bool
+	RequestSegment  int
+	Items           string
+	Enums           bool
+}
+
+func (r GameDataRequest) Endpoint() string {
+	return "/data"
+}
+
+func (r GameDataRequest) Validate() error {
+	if r.Version == "" {
+		return invalidRequest(r.Endpoint(), "Version is required")
+	}
+
+	if r.RequestSegment < 0 {
+		return invalidRequest(r.Endpoint(), "RequestSegment can not be negative")
+	}
+
+	return nil
+}
+
+func (r GameDataRequest) RequestBody() Body {
+	return requestBody(RequestBody{
+		Payload: Payload{Items: r.Items},
+		Enums:   r.Enums,
+	}, func(body *RequestBodyPointer) {
+		body.Payload.Version = Ptr(r.Version)
+		body.Payload.IncludePveUnits = Ptr(r.IncludePveUnits)
+		body.Payload.RequestSegment = Ptr(r.RequestSegment)
+	})
+}
+
+type MetadataRequest struct {
+	ClientSpecs ClientSpecs
+	Enums       bool
+}
+
+func (r MetadataRequest) Endpoint() string {
+	return "/metadata"
+}
+
+func (r MetadataRequest) Validate() error {
+	return nil
+}
+
+func (r MetadataRequest) RequestBody() Body {
+	return requestBody(RequestBody{
+		Payload: Payload{ClientSpecs: r.ClientSpecs},
+		Enums:   r.Enums,
+	}, nil)
+}
+
+type LocalizationRequest struct {
+	// Id is the localization bundle version, latestLocalizationBundleVersion from /metadata.
+	Id    string
+	Unzip bool
+	Enums bool
+}
+
+func (r LocalizationRequest) Endpoint() string {
+	return "/localization"
+}
+
+func (r LocalizationRequest) Validate() error {
+	if r.Id == "" {
+		return invalidRequest(r.Endpoint(), "Id is required")
+	}
+
+	return nil
+}
+
+func (r LocalizationRequest) RequestBody() Body {
+	return requestBody(RequestBody{
+		Payload: Payload{Id: r.Id},
+		Enums:   r.Enums,
+	}, func(body *RequestBodyPointer) {
+		body.Unzip = Ptr(r.Unzip)
+	})
+}
+
+type GetEventsRequest struct {
+	Enums bool
+}
+
+func (r GetEventsRequest) Endpoint() string {
+	return "/GetEvents"
+}
+
+func (r GetEventsRequest) Validate() error {
+	return nil
+}
+
+func (r GetEventsRequest) RequestBody() Body {
+	return requestBody(RequestBody{Enums: r.Enums}, nil)
+}
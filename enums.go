@@ -0,0 +1,257 @@
+package ComlinkGo
+
+//go:generate go run ./cmd/enumgen -in enums.json -out enums_gen.go -only Division,FilterType,GuildLeaderboardType,LeaderboardType,League
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrUnknownEnum      = errors.New("unknown enum")
+	ErrInvalidEnumsBody = errors.New("failed to parse /enums response")
+)
+
+// DefaultEnumFields maps JSON field names found in comlink responses to the enum they hold.
+var DefaultEnumFields = map[string]string{
+	"combatType":    "CombatType",
+	"currency":      "CurrencyType",
+	"memberLevel":   "GuildMemberLevel",
+	"squadUnitType": "SquadUnitType",
+	"tab":           "PlayerPvpTabType",
+	"unitStatId":    "UnitStat",
+}
+
+// EnumRegistry translates enum values between their numeric and named forms using /enums.
+type EnumRegistry struct {
+	// Fields maps a JSON field name to the enum it holds. It starts as a copy of DefaultEnumFields.
+	Fields map[string]string
+
+	values map[string]map[string]int
+	names  map[string]map[int]string
+}
+
+func (c *Comlink) LoadEnumRegistry(ctx context.Context) (*EnumRegistry, error) {
+	raw, err := c.EnumsCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEnumRegistry(raw)
+}
+
+// NewEnumRegistry builds a registry from an /enums response, which looks like {"Enum": {"NAME": 1}}.
+func NewEnumRegistry(raw map[string]any) (*EnumRegistry, error) {
+	registry := &EnumRegistry{
+		Fields: maps.Clone(DefaultEnumFields),
+		values: make(map[string]map[string]int, len(raw)),
+		names:  make(map[string]map[int]string, len(raw)),
+	}
+
+	for enum, rawValues := range raw {
+		entries, ok := rawValues.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not an object", ErrInvalidEnumsBody, enum)
+		}
+
+		registry.values[enum] = make(map[string]int, len(entries))
+		registry.names[enum] = make(map[int]string, len(entries))
+
+		for name, rawNumber := range entries {
+			number, ok := toInt(rawNumber)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s.%s is not a number", ErrInvalidEnumsBody, enum, name)
+			}
+
+			registry.values[enum][name] = number
+
+			if _, exists := registry.names[enum][number]; !exists {
+				registry.names[enum][number] = name
+			}
+		}
+	}
+
+	return registry, nil
+}
+
+func (r *EnumRegistry) Enums() []string {
+	enums := make([]string, 0, len(r.values))
+	for enum := range r.values {
+		enums = append(enums, enum)
+	}
+
+	return enums
+}
+
+func (r *EnumRegistry) Name(enum string, number int) (string, bool) {
+	name, ok := r.names[enum][number]
+
+	return name, ok
+}
+
+func (r *EnumRegistry) Number(enum string, name string) (int, bool) {
+	number, ok := r.values[enum][name]
+
+	return number, ok
+}
+
+// Resolve fills in whichever of Number or Name is missing from value.
+func (r *EnumRegistry) Resolve(enum string, value Enum) (Enum, error) {
+	if value.Name != "" {
+		number, ok := r.Number(enum, value.Name)
+		if !ok {
+			return value, fmt.Errorf("%w: %s has no value %s", ErrUnknownEnum, enum, value.Name)
+		}
+
+		return Enum{Number: number, Name: value.Name}, nil
+	}
+
+	name, ok := r.Name(enum, value.Number)
+	if !ok {
+		return value, fmt.Errorf("%w: %s has no value %d", ErrUnknownEnum, enum, value.Number)
+	}
+
+	return Enum{Number: value.Number, Name: name}, nil
+}
+
+// ToNames returns a copy of a decoded response with every known enum field turned into its name.
+// Typed structs are accepted too and come back as the equivalent map.
+func (r *EnumRegistry) ToNames(response any) (any, error) {
+	return r.convert(response, true)
+}
+
+// ToNumbers is the opposite of ToNames.
+func (r *EnumRegistry) ToNumbers(response any) (any, error) {
+	return r.convert(response, false)
+}
+
+func (r *EnumRegistry) convert(response any, toNames bool) (any, error) {
+	switch response.(type) {
+	case map[string]any, []any:
+	default:
+		data, err := json.Marshal(response)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnknownEnum, err)
+		}
+
+		err = json.Unmarshal(data, &response)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnknownEnum, err)
+		}
+	}
+
+	return r.walk("", response, toNames), nil
+}
+
+func (r *EnumRegistry) walk(field string, value any, toNames bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for key, child := range v {
+			converted[key] = r.walk(key, child, toNames)
+		}
+
+		return converted
+	case []any:
+		converted := make([]any, len(v))
+		for i, child := range v {
+			converted[i] = r.walk(field, child, toNames)
+		}
+
+		return converted
+	}
+
+	enum, ok := r.Fields[field]
+	if !ok {
+		return value
+	}
+
+	if name, ok := value.(string); ok && !toNames {
+		if number, ok := r.Number(enum, name); ok {
+			return number
+		}
+	}
+
+	if number, ok := toInt(value); ok && toNames {
+		if name, ok := r.Name(enum, number); ok {
+			return name
+		}
+	}
+
+	return value
+}
+
+// ResolveEnums fills in both Number and Name of every Enum field in a typed response, such as a
+// *PlayerResponse, using the field's JSON name to find its enum. Unknown fields are left alone.
+func (r *EnumRegistry) ResolveEnums(response any) {
+	r.resolveValue("", reflect.ValueOf(response))
+}
+
+var enumType = reflect.TypeFor[Enum]()
+
+func (r *EnumRegistry) resolveValue(field string, value reflect.Value) {
+	switch value.Kind() { //nolint:exhaustive
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() {
+			r.resolveValue(field, value.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			r.resolveValue(field, value.Index(i))
+		}
+	case reflect.Struct:
+		if value.Type() == enumType {
+			r.resolveEnum(field, value)
+
+			return
+		}
+
+		for i := range value.NumField() {
+			structField := value.Type().Field(i)
+			if !structField.IsExported() {
+				continue
+			}
+
+			name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+			if name == "" {
+				name = structField.Name
+			}
+
+			r.resolveValue(name, value.Field(i))
+		}
+	}
+}
+
+func (r *EnumRegistry) resolveEnum(field string, value reflect.Value) {
+	enum, ok := r.Fields[field]
+	if !ok || !value.CanSet() {
+		return
+	}
+
+	resolved, err := r.Resolve(enum, value.Interface().(Enum)) //nolint:forcetypeassert // Checked by the caller
+	if err != nil {
+		return
+	}
+
+	value.Set(reflect.ValueOf(resolved))
+}
+
+func toInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), v == float64(int(v))
+	case int:
+		return v, true
+	case json.Number:
+		number, err := v.Int64()
+
+		return int(number), err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,74 @@
+package ComlinkGo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+const maxErrorBodySize = 1 << 20
+
+// APIError is returned for any non 200 response from comlink. It wraps ErrBadStatusCode, so both
+// errors.Is(err, ErrBadStatusCode) and errors.As(err, &apiErr) work.
+type APIError struct {
+	StatusCode int
+	Endpoint   string
+	RequestID  string
+	Code       string
+	Message    string
+	// Body is the raw response body, which is not always JSON when a proxy sits in front of comlink.
+	Body []byte
+	// Retryable is whether the RetryPolicy used for the request retries this status code.
+	Retryable bool
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: Status: %d Endpoint: %s Code: %s Message: %s",
+		ErrBadStatusCode, e.StatusCode, e.Endpoint, e.Code, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return ErrBadStatusCode
+}
+
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+
+	if resp.Request != nil {
+		apiErr.Endpoint = resp.Request.URL.Path
+		apiErr.Retryable = httpclient.RetryPolicyFromContext(resp.Request.Context()).ShouldRetryStatus(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		apiErr.Message = fmt.Sprintf("failed to read error body: %v", err)
+
+		return apiErr
+	}
+
+	apiErr.Body = body
+
+	var comlinkError ComlinkError
+
+	err = json.Unmarshal(body, &comlinkError)
+	if err == nil && (comlinkError.Code != "" || comlinkError.Message != "") {
+		apiErr.Code = comlinkError.Code
+		apiErr.Message = comlinkError.Message
+
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(body))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return apiErr
+}
@@ -0,0 +1,282 @@
+package ComlinkGo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMissingGameDataVersion = errors.New("metadata did not include latestGamedataVersion")
+	ErrGameDataCache          = errors.New("failed to use the gamedata cache")
+)
+
+const cacheDirPerm, cacheFilePerm = 0o755, 0o644
+
+// cacheDownloadTimeout bounds a shared /data download, which no longer stops when its first caller does.
+const cacheDownloadTimeout = 5 * time.Minute
+
+// GameDataCache stores /data responses on disk, one directory per gamedata version. Whenever
+// /metadata advertises a new latestGamedataVersion every older version is deleted.
+type GameDataCache struct {
+	Comlink *Comlink
+	Dir     string
+
+	mu       sync.Mutex
+	inFlight map[string]*cacheDownload
+}
+
+// cacheDownload lets concurrent requests for the same file share one /data call.
+type cacheDownload struct {
+	done chan struct{}
+	raw  json.RawMessage
+	err  error
+}
+
+func NewGameDataCache(comlink *Comlink, dir string) (*GameDataCache, error) {
+	err := os.MkdirAll(dir, cacheDirPerm)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGameDataCache, err)
+	}
+
+	return &GameDataCache{
+		Comlink: comlink,
+		Dir:     dir,
+	}, nil
+}
+
+func (g *GameDataCache) GameData(ctx context.Context, payload Body) (map[string]any, error) {
+	raw, err := g.GameDataRaw(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var response map[string]any
+
+	err = json.Unmarshal(raw, &response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGameDataCache, err)
+	}
+
+	return response, nil
+}
+
+// GameDataRaw returns the cached JSON body. If the payload has no Version the latest version from
+// /metadata is used and older versions are removed, otherwise the given version is used as is.
+func (g *GameDataCache) GameDataRaw(ctx context.Context, payload Body) (json.RawMessage, error) {
+	body := payload.requestBodyPointer()
+
+	if body.Payload == nil || deref(body.Payload.Version) == "" {
+		version, err := g.LatestVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// Copied so the caller's PayloadPointer is left alone
+		var withVersion PayloadPointer
+		if body.Payload != nil {
+			withVersion = *body.Payload
+		}
+
+		withVersion.Version = &version
+		body.Payload = &withVersion
+	}
+
+	path := g.path(body)
+
+	raw, err := os.ReadFile(path)
+	if err == nil {
+		return raw, nil
+	}
+
+	g.mu.Lock()
+
+	download, ok := g.inFlight[path]
+	if !ok {
+		if g.inFlight == nil {
+			g.inFlight = make(map[string]*cacheDownload)
+		}
+
+		download = &cacheDownload{done: make(chan struct{})}
+		g.inFlight[path] = download
+
+		// Detached from ctx so one caller giving up does not fail everyone waiting on the download
+		go g.download(context.WithoutCancel(ctx), path, body, download)
+	}
+
+	g.mu.Unlock()
+
+	select {
+	case <-download.done:
+		return download.raw, download.err
+	case <-ctx.Done():
+		return nil, ctx.Err() //nolint:wrapcheck
+	}
+}
+
+func (g *GameDataCache) download(ctx context.Context, path string, body RequestBodyPointer, download *cacheDownload) {
+	defer close(download.done)
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.inFlight, path)
+		g.mu.Unlock()
+	}()
+
+	// Another download may have written the file after the first read missed it
+	raw, err := os.ReadFile(path)
+	if err == nil {
+		download.raw = raw
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cacheDownloadTimeout)
+	defer cancel()
+
+	download.raw, download.err = DecodeResponse[json.RawMessage](g.Comlink.GameDataRawCtx(ctx, body)) //nolint:bodyclose // Handled by DecodeResponse()
+	if download.err != nil {
+		return
+	}
+
+	g.mu.Lock()
+	err = writeFileAtomic(path, download.raw)
+	g.mu.Unlock()
+
+	if err != nil {
+		download.raw, download.err = nil, fmt.Errorf("%w: %w", ErrGameDataCache, err)
+	}
+}
+
+// LatestVersion asks /metadata for the latest gamedata version and drops every other cached version.
+func (g *GameDataCache) LatestVersion(ctx context.Context) (string, error) {
+	metadata, err := g.Comlink.MetadataCtx(ctx, RequestBody{})
+	if err != nil {
+		return "", err
+	}
+
+	version, ok := metadata["latestGamedataVersion"].(string)
+	if !ok || version == "" {
+		return "", ErrMissingGameDataVersion
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	err = g.removeVersionsExcept(version)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrGameDataCache, err)
+	}
+
+	return version, nil
+}
+
+// Invalidate removes every cached version.
+func (g *GameDataCache) Invalidate() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	err := g.removeVersionsExcept("")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrGameDataCache, err)
+	}
+
+	return nil
+}
+
+func (g *GameDataCache) removeVersionsExcept(version string) error {
+	entries, err := os.ReadDir(g.Dir)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	keep := sanitizeFileName(version)
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == keep {
+			continue
+		}
+
+		err = os.RemoveAll(filepath.Join(g.Dir, entry.Name()))
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+func (g *GameDataCache) path(body RequestBodyPointer) string {
+	items := sha256.Sum256([]byte(deref(body.Payload.Items)))
+
+	name := fmt.Sprintf("segment%d_pve%t_enums%t_items%s.json",
+		deref(body.Payload.RequestSegment),
+		deref(body.Payload.IncludePveUnits),
+		deref(body.Enums),
+		hex.EncodeToString(items[:8]),
+	)
+
+	return filepath.Join(g.Dir, sanitizeFileName(deref(body.Payload.Version)), name)
+}
+
+// deref returns the zero value for nil, which is what comlink assumes for a missing field.
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+
+	return *p
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), cacheDirPerm)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+
+		return err //nolint:wrapcheck
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	err = os.Chmod(tmp.Name(), cacheFilePerm)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return os.Rename(tmp.Name(), path) //nolint:wrapcheck
+}
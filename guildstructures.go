@@ -0,0 +1,141 @@
+package ComlinkGo
+
+type GuildResponse struct {
+	Guild Guild `json:"guild"`
+}
+
+// Guild is the guild object in a /guild response. RecentRaidResult, RecentTerritoryWarResult and each member's
+// MemberContribution are only filled in when Payload.IncludeRecentGuildActivityInfo is set.
+type Guild struct {
+	Member                   []GuildMember           `json:"member"`
+	Profile                  GuildProfile            `json:"profile"`
+	NextChallengesRefresh    Int64                   `json:"nextChallengesRefresh"`
+	RecentRaidResult         []RaidResult            `json:"recentRaidResult"`
+	RecentTerritoryWarResult []TerritoryWarResult    `json:"recentTerritoryWarResult"`
+	TerritoryBattleResult    []TerritoryBattleResult `json:"territoryBattleResult"`
+}
+
+type GuildMember struct {
+	PlayerId               string               `json:"playerId"`
+	PlayerName             string               `json:"playerName"`
+	PlayerLevel            int                  `json:"playerLevel"`
+	MemberLevel            Enum                 `json:"memberLevel"`
+	GuildXp                int                  `json:"guildXp"`
+	LeagueId               string               `json:"leagueId"`
+	GalacticPower          Int64                `json:"galacticPower"`
+	CharacterGalacticPower Int64                `json:"characterGalacticPower"`
+	ShipGalacticPower      Int64                `json:"shipGalacticPower"`
+	GuildJoinTime          Int64                `json:"guildJoinTime"`
+	LastActivityTime       Int64                `json:"lastActivityTime"`
+	LifetimeSeasonScore    Int64                `json:"lifetimeSeasonScore"`
+	PlayerTitle            string               `json:"playerTitle"`
+	PlayerPortrait         string               `json:"playerPortrait"`
+	SquadPower             int                  `json:"squadPower"`
+	MemberContribution     []MemberContribution `json:"memberContribution"`
+	SeasonStatus           []SeasonStatus       `json:"seasonStatus"`
+}
+
+type MemberContribution struct {
+	Type          Enum  `json:"type"`
+	CurrentValue  Int64 `json:"currentValue"`
+	LifetimeValue Int64 `json:"lifetimeValue"`
+}
+
+type GuildProfile struct {
+	Id                               string             `json:"id"`
+	Name                             string             `json:"name"`
+	ExternalMessageKey               string             `json:"externalMessageKey"`
+	LogoBackground                   string             `json:"logoBackground"`
+	EnrollmentStatus                 Enum               `json:"enrollmentStatus"`
+	Trophy                           int                `json:"trophy"`
+	MemberCount                      int                `json:"memberCount"`
+	MemberMax                        int                `json:"memberMax"`
+	Level                            int                `json:"level"`
+	Rank                             int                `json:"rank"`
+	LevelRequirement                 int                `json:"levelRequirement"`
+	RaidWin                          int                `json:"raidWin"`
+	RaidLaunchConfig                 []RaidLaunchConfig `json:"raidLaunchConfig"`
+	GuildGalacticPower               Int64              `json:"guildGalacticPower"`
+	GuildGalacticPowerForRequirement Int64              `json:"guildGalacticPowerForRequirement"`
+	GuildType                        string             `json:"guildType"`
+	BannerColorId                    string             `json:"bannerColorId"`
+	BannerLogoId                     string             `json:"bannerLogoId"`
+	AutoAcceptJoinRequests           bool               `json:"autoAcceptJoinRequests"`
+}
+
+type RaidLaunchConfig struct {
+	RaidId                    string                     `json:"raidId"`
+	CampaignMissionIdentifier *CampaignMissionIdentifier `json:"campaignMissionIdentifier"`
+	AutoLaunch                bool                       `json:"autoLaunch"`
+	AutoLaunchTime            Int64                      `json:"autoLaunchTime"`
+	JoinPeriodDuration        Int64                      `json:"joinPeriodDuration"`
+}
+
+type CampaignMissionIdentifier struct {
+	CampaignId             string `json:"campaignId"`
+	CampaignMapId          string `json:"campaignMapId"`
+	CampaignNodeId         string `json:"campaignNodeId"`
+	CampaignNodeDifficulty Enum   `json:"campaignNodeDifficulty"`
+	CampaignMissionId      string `json:"campaignMissionId"`
+}
+
+type RaidResult struct {
+	RaidId           string                     `json:"raidId"`
+	Identifier       *CampaignMissionIdentifier `json:"identifier"`
+	Outcome          Enum                       `json:"outcome"`
+	RaidMember       []RaidMember               `json:"raidMember"`
+	Duration         Int64                      `json:"duration"`
+	EndTime          Int64                      `json:"endTime"`
+	GuildRewardScore Int64                      `json:"guildRewardScore"`
+}
+
+type RaidMember struct {
+	PlayerId       string `json:"playerId"`
+	MemberProgress Int64  `json:"memberProgress"`
+	MemberRank     int    `json:"memberRank"`
+	MemberAttempt  int    `json:"memberAttempt"`
+}
+
+type TerritoryWarResult struct {
+	TerritoryWarId string `json:"territoryWarId"`
+	Score          Int64  `json:"score"`
+	Power          Int64  `json:"power"`
+	OpponentScore  Int64  `json:"opponentScore"`
+	EndTimeSeconds Int64  `json:"endTimeSeconds"`
+}
+
+type TerritoryBattleResult struct {
+	InstanceId     string `json:"instanceId"`
+	DefinitionId   string `json:"definitionId"`
+	Score          Int64  `json:"score"`
+	Stars          int    `json:"stars"`
+	EndTimeSeconds Int64  `json:"endTimeSeconds"`
+}
+
+type GetGuildsResponse struct {
+	Guild      []GuildProfile `json:"guild"`
+	TotalCount int            `json:"totalCount"`
+}
+
+type GuildLeaderboardResponse struct {
+	Leaderboard []GuildLeaderboard `json:"leaderboard"`
+}
+
+type GuildLeaderboard struct {
+	LeaderboardType Enum                    `json:"leaderboardType"`
+	DefId           string                  `json:"defId"`
+	MonthOffset     int                     `json:"monthOffset"`
+	Guild           []GuildLeaderboardEntry `json:"guild"`
+}
+
+type GuildLeaderboardEntry struct {
+	Id                 string `json:"id"`
+	Name               string `json:"name"`
+	Score              Int64  `json:"score"`
+	Rank               int    `json:"rank"`
+	MemberCount        int    `json:"memberCount"`
+	GuildGalacticPower Int64  `json:"guildGalacticPower"`
+	LogoBackground     string `json:"logoBackground"`
+	BannerColorId      string `json:"bannerColorId"`
+	BannerLogoId       string `json:"bannerLogoId"`
+}
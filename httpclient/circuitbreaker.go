@@ -0,0 +1,189 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+const (
+	DefaultFailureThreshold = 5
+	DefaultCoolDown         = 30 * time.Second
+)
+
+// CircuitOpenError is returned without making a request while the breaker is open. It matches
+// errors.Is(err, ErrCircuitOpen).
+type CircuitOpenError struct {
+	// OpenUntil is when the breaker lets a trial request through again.
+	OpenUntil time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrCircuitOpen, e.OpenUntil.Format(time.RFC3339))
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "CircuitState(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+type CircuitBreakerSettings struct {
+	// FailureThreshold is how many failed attempts in a row open the breaker. Network errors and
+	// 5xx responses are failures.
+	FailureThreshold int
+	// CoolDown is how long the breaker stays open before letting trial requests through.
+	CoolDown time.Duration
+	// HalfOpenRequests is how many trial requests are let through while half-open. All of them have
+	// to succeed to close the breaker. Defaults to 1.
+	HalfOpenRequests int
+	// Now tells the time for the cool down. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// CircuitBreaker stops DoWithRetry from calling a comlink that keeps failing. Once open it fails
+// every attempt with a *CircuitOpenError until CoolDown has passed, then lets trial requests
+// through. Once HalfOpenRequests trials have succeeded it closes again, a failed one opens it for
+// another CoolDown.
+type CircuitBreaker struct {
+	settings CircuitBreakerSettings
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openUntil time.Time
+	trials    int
+	successes int
+}
+
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = DefaultFailureThreshold
+	}
+
+	if settings.CoolDown <= 0 {
+		settings.CoolDown = DefaultCoolDown
+	}
+
+	if settings.HalfOpenRequests <= 0 {
+		settings.HalfOpenRequests = 1
+	}
+
+	if settings.Now == nil {
+		settings.Now = time.Now
+	}
+
+	return &CircuitBreaker{settings: settings}
+}
+
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && !b.settings.Now().Before(b.openUntil) {
+		return CircuitHalfOpen
+	}
+
+	return b.state
+}
+
+// Allow reports whether an attempt may be made. Every nil return has to be followed by Record.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen {
+		if b.settings.Now().Before(b.openUntil) {
+			return &CircuitOpenError{OpenUntil: b.openUntil}
+		}
+
+		b.state = CircuitHalfOpen
+		b.trials = 0
+		b.successes = 0
+	}
+
+	if b.state == CircuitHalfOpen {
+		if b.trials >= b.settings.HalfOpenRequests {
+			return &CircuitOpenError{OpenUntil: b.openUntil}
+		}
+
+		b.trials++
+	}
+
+	return nil
+}
+
+func (b *CircuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		switch b.state {
+		case CircuitClosed:
+			b.failures = 0
+		case CircuitHalfOpen:
+			b.successes++
+
+			if b.successes >= b.settings.HalfOpenRequests {
+				b.state = CircuitClosed
+				b.failures = 0
+				b.trials = 0
+			}
+		case CircuitOpen:
+			// A late answer to an attempt made before the breaker opened is not a trial
+		}
+
+		return
+	}
+
+	b.failures++
+
+	if b.state == CircuitHalfOpen || b.failures >= b.settings.FailureThreshold {
+		b.state = CircuitOpen
+		b.openUntil = b.settings.Now().Add(b.settings.CoolDown)
+		b.trials = 0
+	}
+}
+
+// RecordAttempt is Record for the result of the attempt in ctx. An attempt that never reached
+// comlink, or that its caller cancelled or timed out, says nothing about comlink, so it only gives
+// back its trial slot.
+func (b *CircuitBreaker) RecordAttempt(ctx context.Context, resp *http.Response, err error) {
+	if !AttemptReachedComlink(ctx) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if b.state == CircuitHalfOpen && b.trials > 0 {
+			b.trials--
+		}
+
+		return
+	}
+
+	b.Record(err == nil && resp.StatusCode < http.StatusInternalServerError)
+}
@@ -0,0 +1,63 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type clientCtxKey struct{}
+
+// WithClientContext returns a ctx that is cancelled when either ctx or the client wide Ctx is, so
+// shutting the client down also stops calls made with their own context. cancel releases the link
+// to Ctx and has to be called once the work using ctx is done.
+func (c *HTTPClient) WithClientContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.Ctx == nil || ctx == c.Ctx || ctx.Value(clientCtxKey{}) == c {
+		return ctx, func() {}
+	}
+
+	merged, cancel := context.WithCancelCause(context.WithValue(ctx, clientCtxKey{}, c))
+
+	if c.Ctx.Err() != nil {
+		cancel(context.Cause(c.Ctx))
+
+		return merged, func() {}
+	}
+
+	stop := context.AfterFunc(c.Ctx, func() {
+		cancel(context.Cause(c.Ctx))
+	})
+
+	return merged, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// ReleaseOnClose calls release once the response body is closed, or right away when there is no
+// response. Use it in an Interceptor to hold on to something for as long as the body is being read.
+func ReleaseOnClose(resp *http.Response, err error, release func()) (*http.Response, error) {
+	if resp == nil || resp.Body == nil {
+		release()
+
+		return resp, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, err
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+
+	return err //nolint:wrapcheck
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"sync"
@@ -14,6 +15,7 @@ import (
 
 var ErrMaxRetriesExceeded = errors.New("max retries exceeded")
 var ErrUnknownHTTP = errors.New("unknown error making http.Do")
+var ErrRetryableStatus = errors.New("got a retryable status code")
 
 var Client *HTTPClient
 
@@ -28,13 +30,66 @@ const (
 )
 
 type HTTPClient struct {
-	Client *http.Client
-	Ctx    context.Context
-	Wg     *sync.WaitGroup
+	Client      *http.Client
+	Ctx         context.Context
+	Wg          *sync.WaitGroup
+	RetryPolicy RetryPolicy
+	RateLimiter *RateLimiter
+	// Interceptors wrap the whole DoWithRetry call and see only the final response.
+	Interceptors []Interceptor
+	// AttemptInterceptors wrap every single attempt, so they also see the responses that get retried.
+	AttemptInterceptors []Interceptor
+	// Logger gets a line for every request, attempt and retry. Nil disables logging.
+	Logger *slog.Logger
+	// Metrics, when set, is told about every request and retry.
+	Metrics Metrics
+	// Tracer, when set, gets a span for every call and a child span for every attempt.
+	Tracer Tracer
+	// CircuitBreaker, when set, fails attempts with a *CircuitOpenError while comlink is unhealthy.
+	CircuitBreaker *CircuitBreaker
+	// SkipRetryDelay, when set, is asked before every retry whether the next attempt can go out
+	// without waiting, for example because it will be sent to a different server.
+	SkipRetryDelay func(ctx context.Context) bool
 }
 
+// Init creates an HTTPClient and also stores it in the package level Client.
+//
+// Deprecated: Every call replaces Client, so use New, which does not touch any globals.
 func Init(ctx context.Context, wg *sync.WaitGroup) *HTTPClient {
-	transport := &http.Transport{
+	Client = New(ctx, wg, nil)
+
+	return Client
+}
+
+// New creates an HTTPClient around client. A nil client gets the default from NewClient(nil).
+func New(ctx context.Context, wg *sync.WaitGroup, client *http.Client) *HTTPClient {
+	if client == nil {
+		client = NewClient(nil)
+	}
+
+	return &HTTPClient{
+		Client:      client,
+		Ctx:         ctx,
+		Wg:          wg,
+		RetryPolicy: DefaultRetryPolicy(),
+	}
+}
+
+// NewClient creates an *http.Client with the default timeout around transport. A nil transport
+// gets the default from DefaultTransport().
+func NewClient(transport http.RoundTripper) *http.Client {
+	if transport == nil {
+		transport = DefaultTransport()
+	}
+
+	return &http.Client{
+		Timeout:   thirtySeconds,
+		Transport: transport,
+	}
+}
+
+func DefaultTransport() *http.Transport {
+	return &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   fiveSeconds,
 			KeepAlive: thirtySeconds,
@@ -43,50 +98,176 @@ func Init(ctx context.Context, wg *sync.WaitGroup) *HTTPClient {
 		IdleConnTimeout:     ninetySeconds,
 		TLSHandshakeTimeout: fifteenSeconds,
 	}
+}
+
+func (c *HTTPClient) DoWithRetry(req *http.Request) (*http.Response, error) {
+	return Chain(c.doWithRetry, c.Interceptors...)(req)
+}
+
+func (c *HTTPClient) doWithRetry(req *http.Request) (*http.Response, error) {
+	ctx, release := c.WithClientContext(req.Context())
+	req = req.WithContext(ctx)
+
+	resp, err := c.observedRetryLoop(req)
+
+	return ReleaseOnClose(resp, err, release)
+}
+
+func (c *HTTPClient) observedRetryLoop(req *http.Request) (*http.Response, error) {
+	logger := c.logger()
+	req = req.WithContext(context.WithValue(req.Context(), loggerKey{}, logger))
+	req = req.WithContext(context.WithValue(req.Context(), retryPolicyKey{}, c.RetryPolicy))
+
+	ctx, span := c.startSpan(req.Context(), "comlink "+req.URL.Path,
+		spanAttributes(req.Context(), Attribute{Key: AttributeEndpoint, Value: req.URL.Path})...)
+	req = req.WithContext(ctx)
+	start := time.Now()
+
+	logger.DebugContext(ctx, "comlink request started", "method", req.Method, "endpoint", req.URL.Path)
+
+	if c.Metrics != nil {
+		c.Metrics.RequestStarted(req.URL.Path)
+	}
+
+	resp, attempts, err := c.retryLoop(req)
 
-	Client = &HTTPClient{
-		Client: &http.Client{
-			Timeout:   thirtySeconds,
-			Transport: transport,
-		},
-		Ctx: ctx,
-		Wg:  wg,
+	endSpan(span, resp, err)
+
+	if c.Metrics != nil {
+		c.Metrics.RequestFinished(req.URL.Path, statusCode(resp), attempts, time.Since(start))
 	}
 
-	return Client
+	attrs := append(logAttrs(req, resp, err), slog.Int("attempts", attempts), slog.Duration("latency", time.Since(start)))
+
+	level := slog.LevelInfo
+	if err != nil || resp.StatusCode != http.StatusOK {
+		level = slog.LevelError
+	}
+
+	logger.LogAttrs(ctx, level, "comlink request finished", attrs...)
+
+	return resp, err
 }
 
-func (c *HTTPClient) DoWithRetry(req *http.Request) (*http.Response, error) {
-	var err error
+func (c *HTTPClient) retryLoop(req *http.Request) (*http.Response, int, error) {
+	logger := c.logger()
 
 	reqRoot, _, err := cloneRequest(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	for attempt := range maxRetries {
+	attempts := c.RetryPolicy.attempts()
+	do := Chain(c.DoWithoutRetry, c.AttemptInterceptors...)
+
+	for attempt := range attempts {
 		var reqTemp *http.Request
 
 		reqTemp, reqRoot, err = cloneRequest(reqRoot)
 		if err != nil {
-			return nil, fmt.Errorf("%w: %w", ErrUnknownHTTP, err)
+			return nil, attempt, fmt.Errorf("%w: %w", ErrUnknownHTTP, err)
+		}
+
+		if c.CircuitBreaker != nil {
+			errr := c.CircuitBreaker.Allow()
+			if errr != nil {
+				return nil, attempt, errr
+			}
+		}
+
+		attemptStart := time.Now()
+		attemptCtx := withAttempt(withTransportFlag(reqTemp.Context()), attempt+1)
+
+		attemptCtx, attemptSpan := c.startSpan(attemptCtx, "comlink attempt",
+			Attribute{Key: AttributeEndpoint, Value: reqTemp.URL.Path}, Attribute{Key: AttributeAttempt, Value: attempt + 1})
+
+		if traceParent := attemptSpan.TraceParent(); traceParent != "" {
+			reqTemp.Header.Set("traceparent", traceParent)
+		}
+
+		resp, errr := do(reqTemp.WithContext(attemptCtx))
+
+		endSpan(attemptSpan, resp, errr)
+
+		if c.CircuitBreaker != nil {
+			c.CircuitBreaker.RecordAttempt(attemptCtx, resp, errr)
+		}
+
+		logger.LogAttrs(reqRoot.Context(), slog.LevelDebug, "comlink attempt finished",
+			append(logAttrs(reqRoot, resp, errr), slog.Int("attempt", attempt+1), slog.Duration("latency", time.Since(attemptStart)))...)
+
+		// An AttemptInterceptor with its own breaker, like the one in ComlinkPool, refused the attempt
+		if errors.Is(errr, ErrCircuitOpen) {
+			return nil, attempt, errr
+		}
+
+		if errr == nil && (!c.RetryPolicy.ShouldRetryStatus(resp.StatusCode) || attempt == attempts-1) {
+			return resp, attempt + 1, nil
+		}
+
+		delay := c.RetryPolicy.Delay(attempt, resp)
+		if c.SkipRetryDelay != nil && c.SkipRetryDelay(reqRoot.Context()) {
+			delay = 0
+		}
+
+		if attempt < attempts-1 {
+			logger.LogAttrs(reqRoot.Context(), slog.LevelWarn, "comlink retrying request",
+				append(logAttrs(reqRoot, resp, errr), slog.Int("attempt", attempt+1), slog.Duration("delay", delay))...)
+
+			if c.Metrics != nil {
+				c.Metrics.RequestRetried(reqRoot.URL.Path, statusCode(resp))
+			}
 		}
 
-		resp, errr := c.DoWithoutRetry(reqTemp)
 		if errr == nil {
-			return resp, nil
+			errr = fmt.Errorf("%w: %d", ErrRetryableStatus, resp.StatusCode)
+
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
 		}
 
 		err = errr
 
-		time.Sleep(retryDelay << attempt)
+		if attempt == attempts-1 {
+			break
+		}
+
+		// No point waiting out the delay when the next attempt will be refused anyway
+		if c.CircuitBreaker != nil && c.CircuitBreaker.State() == CircuitOpen {
+			continue
+		}
+
+		errr = c.sleep(reqRoot.Context(), delay)
+		if errr != nil {
+			return nil, attempt + 1, errr
+		}
 	}
 
 	if err == nil {
 		err = ErrMaxRetriesExceeded
 	}
 
-	return nil, err
+	return nil, attempts, err
+}
+
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+
+	return resp.StatusCode
+}
+
+func (c *HTTPClient) sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
 }
 
 func cloneRequest(req *http.Request) (*http.Request, *http.Request, error) {
@@ -119,19 +300,48 @@ func (c *HTTPClient) DoWithoutRetry(req *http.Request) (*http.Response, error) {
 	c.Wg.Add(1)
 	defer c.Wg.Done()
 
-	req = req.WithContext(c.Ctx)
+	ctx, release := c.WithClientContext(req.Context())
+	req = req.WithContext(ctx)
+
+	if c.RateLimiter != nil {
+		err := c.RateLimiter.Wait(req.Context(), req.URL.Path)
+		if err != nil {
+			release()
+
+			return nil, err
+		}
+	}
+
+	markTransportCalled(req.Context())
 
-	return c.Client.Do(req)
+	resp, err := c.Client.Do(req)
+
+	return ReleaseOnClose(resp, err, release)
 }
 
 func (c *HTTPClient) Get(url string) (*http.Response, error) {
-	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	return c.GetCtx(c.Ctx, url)
+}
+
+func (c *HTTPClient) GetCtx(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownHTTP, err)
+	}
 
 	return c.DoWithRetry(req)
 }
 
 func (c *HTTPClient) Post(url string, contentType string, body io.Reader) (*http.Response, error) {
-	req, _ := http.NewRequest(http.MethodPost, url, body)
+	return c.PostCtx(c.Ctx, url, contentType, body)
+}
+
+func (c *HTTPClient) PostCtx(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownHTTP, err)
+	}
+
 	req.Header.Set("Content-Type", contentType)
 
 	return c.DoWithRetry(req)
@@ -0,0 +1,82 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type Doer func(req *http.Request) (*http.Response, error)
+
+// Interceptor wraps a Doer. It can change the request, look at the response or skip next entirely.
+type Interceptor func(next Doer) Doer
+
+// Chain wraps final in interceptors. The first interceptor is the outermost one.
+func Chain(final Doer, interceptors ...Interceptor) Doer {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		final = interceptors[i](final)
+	}
+
+	return final
+}
+
+type attemptKey struct{}
+
+// AttemptFromContext returns the 1 based attempt number inside DoWithRetry, or 0 outside of it.
+func AttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+
+	return attempt
+}
+
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+type transportCalledKey struct{}
+
+// AttemptReachedComlink reports whether the attempt in ctx was handed to the http.Client and was not
+// cancelled or timed out by its caller, so its result says something about comlink.
+func AttemptReachedComlink(ctx context.Context) bool {
+	sent, _ := ctx.Value(transportCalledKey{}).(*atomic.Bool)
+
+	return sent != nil && sent.Load() && ctx.Err() == nil
+}
+
+func withTransportFlag(ctx context.Context) context.Context {
+	return context.WithValue(ctx, transportCalledKey{}, &atomic.Bool{})
+}
+
+func markTransportCalled(ctx context.Context) {
+	if sent, ok := ctx.Value(transportCalledKey{}).(*atomic.Bool); ok {
+		sent.Store(true)
+	}
+}
+
+func HeaderInterceptor(key, value string) Interceptor {
+	return func(next Doer) Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set(key, value)
+
+			return next(req)
+		}
+	}
+}
+
+func UserAgentInterceptor(userAgent string) Interceptor {
+	return HeaderInterceptor("User-Agent", userAgent)
+}
+
+// ObserveInterceptor calls observe after every request with how long it took. resp may be nil.
+func ObserveInterceptor(observe func(req *http.Request, resp *http.Response, err error, duration time.Duration)) Interceptor {
+	return func(next Doer) Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			observe(req, resp, err, time.Since(start))
+
+			return resp, err
+		}
+	}
+}
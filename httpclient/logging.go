@@ -0,0 +1,76 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+)
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+type loggerKey struct{}
+
+// LoggerFromContext returns the logger DoWithRetry attached to a request, or one that discards
+// everything. It lets code holding only a response, such as a decoder, log with the same logger.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerKey{}).(*slog.Logger)
+	if !ok {
+		return discardLogger
+	}
+
+	return logger
+}
+
+func (c *HTTPClient) logger() *slog.Logger {
+	if c.Logger == nil {
+		return discardLogger
+	}
+
+	return c.Logger
+}
+
+// ErrorCategory sorts the result of a request into a short label that is useful in logs and metrics.
+// It returns "" for a successful response.
+func ErrorCategory(resp *http.Response, err error) string {
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case err != nil:
+		return "network"
+	case resp == nil, resp.StatusCode < http.StatusBadRequest:
+		return ""
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "rate_limited"
+	case resp.StatusCode < http.StatusInternalServerError:
+		return "client_error"
+	default:
+		return "server_error"
+	}
+}
+
+// logAttrs leaves out every header so the HMAC Authorization and X-Date never reach the logs.
+func logAttrs(req *http.Request, resp *http.Response, err error) []slog.Attr {
+	attrs := []slog.Attr{slog.String("endpoint", req.URL.Path)}
+
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+
+	if category := ErrorCategory(resp, err); category != "" {
+		attrs = append(attrs, slog.String("error_category", category))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	return attrs
+}
@@ -0,0 +1,196 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics is told about every request made through DoWithRetry. Endpoint is the URL path and status
+// is 0 when there was no response. PrometheusMetrics is a ready made implementation.
+type Metrics interface {
+	RequestStarted(endpoint string)
+	RequestFinished(endpoint string, status int, attempts int, duration time.Duration)
+	RequestRetried(endpoint string, status int)
+}
+
+// DefaultBuckets are the upper bounds in seconds of the request duration histogram.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// AttemptBuckets are the upper bounds of the attempts per request histogram.
+var AttemptBuckets = []float64{1, 2, 3, 4, 5, 10}
+
+// PrometheusMetrics keeps counters in memory and serves them in the Prometheus text format.
+type PrometheusMetrics struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[metricKey]uint64
+	retries   map[metricKey]uint64
+	inFlight  map[string]int64
+	durations map[string]*histogram
+	attempts  map[string]*histogram
+}
+
+type metricKey struct {
+	endpoint string
+	status   int
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewPrometheusMetrics uses DefaultBuckets when no buckets are given.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return &PrometheusMetrics{
+		buckets:   buckets,
+		requests:  make(map[metricKey]uint64),
+		retries:   make(map[metricKey]uint64),
+		inFlight:  make(map[string]int64),
+		durations: make(map[string]*histogram),
+		attempts:  make(map[string]*histogram),
+	}
+}
+
+func (m *PrometheusMetrics) RequestStarted(endpoint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight[endpoint]++
+}
+
+func (m *PrometheusMetrics) RequestFinished(endpoint string, status int, attempts int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight[endpoint]--
+	m.requests[metricKey{endpoint, status}]++
+
+	observe(m.durations, endpoint, m.buckets, duration.Seconds())
+	observe(m.attempts, endpoint, AttemptBuckets, float64(attempts))
+}
+
+func observe(histograms map[string]*histogram, endpoint string, buckets []float64, value float64) {
+	h, ok := histograms[endpoint]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(buckets))}
+		histograms[endpoint] = h
+	}
+
+	for i, bucket := range buckets {
+		if value <= bucket {
+			h.counts[i]++
+		}
+	}
+
+	h.sum += value
+	h.count++
+}
+
+func (m *PrometheusMetrics) RequestRetried(endpoint string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retries[metricKey{endpoint, status}]++
+}
+
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	_ = m.Write(w)
+}
+
+// Write writes every metric in the Prometheus text exposition format.
+func (m *PrometheusMetrics) Write(w io.Writer) error {
+	var buf strings.Builder
+
+	m.mu.Lock()
+
+	writeHeader(&buf, "comlink_requests_total", "counter", "Finished comlink requests by endpoint and status code, 0 when there was no response.")
+
+	for _, key := range sortedKeys(m.requests) {
+		fmt.Fprintf(&buf, "comlink_requests_total{endpoint=%s,code=\"%d\"} %d\n", quote(key.endpoint), key.status, m.requests[key])
+	}
+
+	writeHeader(&buf, "comlink_retries_total", "counter", "Retried comlink attempts by endpoint and the status code that caused the retry.")
+
+	for _, key := range sortedKeys(m.retries) {
+		fmt.Fprintf(&buf, "comlink_retries_total{endpoint=%s,code=\"%d\"} %d\n", quote(key.endpoint), key.status, m.retries[key])
+	}
+
+	writeHeader(&buf, "comlink_requests_in_flight", "gauge", "Comlink requests that have not finished yet.")
+
+	for _, endpoint := range slices.Sorted(maps.Keys(m.inFlight)) {
+		fmt.Fprintf(&buf, "comlink_requests_in_flight{endpoint=%s} %d\n", quote(endpoint), m.inFlight[endpoint])
+	}
+
+	writeHeader(&buf, "comlink_request_duration_seconds", "histogram", "Comlink request duration including retries.")
+	writeHistograms(&buf, "comlink_request_duration_seconds", m.durations, m.buckets)
+
+	writeHeader(&buf, "comlink_request_attempts", "histogram", "Attempts made per comlink request, 1 when it was not retried.")
+	writeHistograms(&buf, "comlink_request_attempts", m.attempts, AttemptBuckets)
+
+	m.mu.Unlock()
+
+	_, err := io.WriteString(w, buf.String())
+
+	return err //nolint:wrapcheck
+}
+
+func writeHistograms(buf *strings.Builder, name string, histograms map[string]*histogram, buckets []float64) {
+	for _, endpoint := range slices.Sorted(maps.Keys(histograms)) {
+		h := histograms[endpoint]
+
+		for i, bucket := range buckets {
+			fmt.Fprintf(buf, "%s_bucket{endpoint=%s,le=\"%s\"} %d\n", name, quote(endpoint), formatFloat(bucket), h.counts[i])
+		}
+
+		fmt.Fprintf(buf, "%s_bucket{endpoint=%s,le=\"+Inf\"} %d\n", name, quote(endpoint), h.count)
+		fmt.Fprintf(buf, "%s_sum{endpoint=%s} %s\n", name, quote(endpoint), formatFloat(h.sum))
+		fmt.Fprintf(buf, "%s_count{endpoint=%s} %d\n", name, quote(endpoint), h.count)
+	}
+}
+
+func writeHeader(buf *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func sortedKeys(m map[metricKey]uint64) []metricKey {
+	keys := slices.Collect(maps.Keys(m))
+
+	slices.SortFunc(keys, func(a, b metricKey) int {
+		if a.endpoint != b.endpoint {
+			return strings.Compare(a.endpoint, b.endpoint)
+		}
+
+		return a.status - b.status
+	})
+
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
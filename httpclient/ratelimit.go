@@ -0,0 +1,138 @@
+package httpclient
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RateLimit struct {
+	// RequestsPerSecond of 0 means unlimited.
+	RequestsPerSecond float64
+	// Burst is how many requests can go out back to back before being limited. Anything below 1 means 1.
+	Burst int
+}
+
+type RateLimitSettings struct {
+	Global RateLimit
+	// Endpoints are keyed by the comlink endpoint, such as "/player". Keys are case insensitive.
+	Endpoints map[string]RateLimit
+}
+
+type RateLimiter struct {
+	global    *tokenBucket
+	endpoints map[string]*tokenBucket
+}
+
+func NewRateLimiter(settings RateLimitSettings) *RateLimiter {
+	limiter := &RateLimiter{
+		global:    newTokenBucket(settings.Global),
+		endpoints: make(map[string]*tokenBucket, len(settings.Endpoints)),
+	}
+
+	for endpoint, limit := range settings.Endpoints {
+		limiter.endpoints[endpointKey(endpoint)] = newTokenBucket(limit)
+	}
+
+	return limiter
+}
+
+// Wait blocks until both the global and the endpoint limits allow a request to urlPath.
+func (l *RateLimiter) Wait(ctx context.Context, urlPath string) error {
+	endpoint := l.endpoints[endpointKey(urlPath)]
+
+	err := endpoint.wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = l.global.wait(ctx)
+	if err != nil {
+		// The request is not going out, so give back the endpoint token it already took
+		endpoint.release()
+
+		return err
+	}
+
+	return nil
+}
+
+func endpointKey(urlPath string) string {
+	return "/" + strings.ToLower(path.Base(urlPath))
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	burst := float64(max(limit.Burst, 1))
+
+	return &tokenBucket{
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		b.release()
+
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token, going into debt if there are none, and returns how long to wait for it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) release() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.burst, b.tokens+1)
+}
@@ -0,0 +1,105 @@
+package httpclient
+
+import (
+	"context"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first one. Anything below 1 means 1.
+	MaxAttempts int
+	// BaseDelay is doubled after every failed attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps any single wait, including ones asked for by Retry-After. 0 means no cap.
+	MaxDelay time.Duration
+	// Jitter is the fraction (0 to 1) of each delay that is randomly taken off.
+	Jitter float64
+	// RetryableStatusCodes are retried like transport errors. The last response is returned as is.
+	RetryableStatusCodes []int
+	RespectRetryAfter    bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       maxRetries,
+		BaseDelay:         retryDelay,
+		RespectRetryAfter: true,
+	}
+}
+
+func (p RetryPolicy) attempts() int {
+	return max(p.MaxAttempts, 1)
+}
+
+// ShouldRetryStatus reports whether a response with statusCode is retried.
+func (p RetryPolicy) ShouldRetryStatus(statusCode int) bool {
+	return slices.Contains(p.RetryableStatusCodes, statusCode)
+}
+
+type retryPolicyKey struct{}
+
+// RetryPolicyFromContext returns the RetryPolicy DoWithRetry used for a request, or
+// DefaultRetryPolicy() when ctx did not come from DoWithRetry.
+func RetryPolicyFromContext(ctx context.Context) RetryPolicy {
+	policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy)
+	if !ok {
+		return DefaultRetryPolicy()
+	}
+
+	return policy
+}
+
+func (p RetryPolicy) Delay(attempt int, resp *http.Response) time.Duration {
+	if p.RespectRetryAfter && resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return p.capDelay(delay)
+		}
+	}
+
+	delay := time.Duration(math.MaxInt64)
+
+	// Only shift while the result still fits in time.Duration
+	if p.BaseDelay <= 0 || attempt < bits.LeadingZeros64(uint64(p.BaseDelay)) {
+		delay = max(p.BaseDelay, 0) << attempt
+	}
+
+	delay = p.capDelay(delay)
+
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * min(p.Jitter, 1) * float64(delay)) //nolint:gosec
+	}
+
+	return delay
+}
+
+func (p RetryPolicy) capDelay(delay time.Duration) time.Duration {
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+
+	return delay
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(time.Until(date), 0), true
+}
@@ -0,0 +1,85 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Tracer starts spans. DoWithRetry starts one span per call and a child span per attempt, so it is
+// easy to back with OpenTelemetry or anything else without this package depending on it.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+	// TraceParent returns the W3C traceparent header for the span, or "" to not send one.
+	TraceParent() string
+}
+
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+const (
+	AttributeEndpoint     = "comlink.endpoint"
+	AttributeAttempt      = "comlink.attempt"
+	AttributeAllyCodeHash = "comlink.ally_code_hash"
+	AttributeStatusCode   = "http.response.status_code"
+)
+
+// FormatTraceParent builds a version 00 W3C traceparent header value.
+func FormatTraceParent(traceID [16]byte, spanID [8]byte, sampled bool) string {
+	flags := 0
+	if sampled {
+		flags = 1
+	}
+
+	return fmt.Sprintf("00-%x-%x-%02x", traceID, spanID, flags)
+}
+
+type spanAttributesKey struct{}
+
+// WithSpanAttributes adds attributes to the call span DoWithRetry starts for requests using ctx.
+func WithSpanAttributes(ctx context.Context, attrs ...Attribute) context.Context {
+	existing, _ := ctx.Value(spanAttributesKey{}).([]Attribute)
+
+	return context.WithValue(ctx, spanAttributesKey{}, append(existing[:len(existing):len(existing)], attrs...))
+}
+
+func spanAttributes(ctx context.Context, attrs ...Attribute) []Attribute {
+	existing, _ := ctx.Value(spanAttributesKey{}).([]Attribute)
+
+	return append(attrs, existing...)
+}
+
+func (c *HTTPClient) startSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	if c.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return c.Tracer.Start(ctx, name, attrs...)
+}
+
+func endSpan(span Span, resp *http.Response, err error) {
+	if resp != nil {
+		span.SetAttributes(Attribute{Key: AttributeStatusCode, Value: resp.StatusCode})
+	}
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	span.End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+func (noopSpan) TraceParent() string        { return "" }
@@ -0,0 +1,64 @@
+package ComlinkGo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// Int64 decodes 64 bit integers that comlink sends as either JSON numbers or quoted strings.
+type Int64 int64
+
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*i = 0
+
+		return nil
+	}
+
+	v, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	*i = Int64(v)
+
+	return nil
+}
+
+// Enum holds an enum value from comlink. Depending on RequestBody.Enums it is either numeric or named.
+type Enum struct {
+	Number int
+	Name   string
+}
+
+func (e *Enum) UnmarshalJSON(data []byte) error {
+	*e = Enum{}
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &e.Name) //nolint:wrapcheck
+	}
+
+	return json.Unmarshal(data, &e.Number) //nolint:wrapcheck
+}
+
+func (e Enum) MarshalJSON() ([]byte, error) {
+	if e.Name != "" {
+		return json.Marshal(e.Name) //nolint:wrapcheck
+	}
+
+	return json.Marshal(e.Number) //nolint:wrapcheck
+}
+
+func (e Enum) String() string {
+	if e.Name != "" {
+		return e.Name
+	}
+
+	return strconv.Itoa(e.Number)
+}
@@ -0,0 +1,172 @@
+package ComlinkGo
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	ErrMissingLocalizationVersion = errors.New("metadata did not include latestLocalizationBundleVersion")
+	ErrInvalidLocalization        = errors.New("failed to parse localization bundle")
+)
+
+const (
+	localizationFilePrefix = "Loc_"
+	localizationFileSuffix = ".txt"
+	localizationZipField   = "localizationBundle"
+)
+
+// LocalizationBundle maps a language, such as ENG_US, to its key -> string dictionary.
+type LocalizationBundle map[string]map[string]string
+
+func (c *Comlink) LocalizationTyped(payload Body) (LocalizationBundle, error) {
+	return c.LocalizationTypedCtx(c.Ctx, payload)
+}
+
+// LocalizationTypedCtx works with both Unzip true and false. A zipped bundle is decoded locally.
+func (c *Comlink) LocalizationTypedCtx(ctx context.Context, payload Body) (LocalizationBundle, error) {
+	response, err := c.LocalizationCtx(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseLocalizationResponse(response)
+}
+
+// LatestLocalization fetches the bundle for latestLocalizationBundleVersion from /metadata.
+func (c *Comlink) LatestLocalization(ctx context.Context, unzip bool) (LocalizationBundle, error) {
+	metadata, err := c.MetadataCtx(ctx, RequestBody{})
+	if err != nil {
+		return nil, err
+	}
+
+	version, ok := metadata["latestLocalizationBundleVersion"].(string)
+	if !ok || version == "" {
+		return nil, ErrMissingLocalizationVersion
+	}
+
+	return c.LocalizationTypedCtx(ctx, RequestBody{
+		Payload: Payload{
+			Id: version,
+		},
+		Unzip: unzip,
+	})
+}
+
+// ParseLocalizationResponse parses a /localization response made with either Unzip true or false.
+func ParseLocalizationResponse(response map[string]any) (LocalizationBundle, error) {
+	if encoded, ok := response[localizationZipField].(string); ok {
+		return parseLocalizationZip(encoded)
+	}
+
+	bundle := make(LocalizationBundle)
+
+	for name, value := range response {
+		language, ok := localizationLanguage(name)
+		if !ok {
+			continue
+		}
+
+		contents, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not a string", ErrInvalidLocalization, name)
+		}
+
+		entries, err := ParseLocalizationFile(strings.NewReader(contents))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidLocalization, name, err)
+		}
+
+		bundle[language] = entries
+	}
+
+	return bundle, nil
+}
+
+// ParseLocalizationFile parses a single Loc_*.txt file made of KEY|value lines. Lines starting with # are comments.
+func ParseLocalizationFile(r io.Reader) (map[string]string, error) {
+	entries := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1<<20)
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "|")
+		if !ok {
+			continue
+		}
+
+		entries[key] = value
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLocalization, err)
+	}
+
+	return entries, nil
+}
+
+func parseLocalizationZip(encoded string) (LocalizationBundle, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLocalization, err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLocalization, err)
+	}
+
+	bundle := make(LocalizationBundle)
+
+	for _, file := range reader.File {
+		language, ok := localizationLanguage(file.Name)
+		if !ok {
+			continue
+		}
+
+		entries, err := parseZipFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidLocalization, file.Name, err)
+		}
+
+		bundle[language] = entries
+	}
+
+	return bundle, nil
+}
+
+func parseZipFile(file *zip.File) (map[string]string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	defer rc.Close()
+
+	return ParseLocalizationFile(rc)
+}
+
+func localizationLanguage(name string) (string, bool) {
+	name = path.Base(name)
+
+	if !strings.HasPrefix(name, localizationFilePrefix) || !strings.HasSuffix(name, localizationFileSuffix) {
+		return "", false
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(name, localizationFilePrefix), localizationFileSuffix), true
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"sync"
@@ -28,10 +29,35 @@ type HMACSettings struct {
 }
 
 type ComlinkSettings struct {
-	ComlinkURL string
-	HMAC       HMACSettings
-	Ctx        context.Context
-	Wg         *sync.WaitGroup
+	ComlinkURL  string
+	HMAC        HMACSettings
+	Ctx         context.Context
+	Wg          *sync.WaitGroup
+	RetryPolicy *httpclient.RetryPolicy
+	RateLimit   *httpclient.RateLimitSettings
+	// Credentials takes priority over HMAC and is asked for keys on every request.
+	Credentials CredentialsProvider
+	Clock       Clock
+	// HTTPClient is used as is when set. Otherwise Transport, if set, is wrapped in a client with
+	// the default timeout.
+	HTTPClient *http.Client
+	Transport  http.RoundTripper
+	// Interceptors wrap every call and AttemptInterceptors wrap every retry attempt. See httpclient.Interceptor.
+	Interceptors        []httpclient.Interceptor
+	AttemptInterceptors []httpclient.Interceptor
+	// Logger gets request, retry and decode logs. Payloads are only logged at debug level, with
+	// ally codes redacted. Headers, Authorization and X-Date included, are never logged. Nil
+	// disables logging.
+	Logger *slog.Logger
+	// Metrics is told about every request. httpclient.NewPrometheusMetrics() also serves them over HTTP.
+	Metrics httpclient.Metrics
+	// Tracer gets a span per call and per attempt. The traceparent header of each attempt is sent.
+	Tracer httpclient.Tracer
+	// AllyCodeHashKey keys the HMAC used for the ally code hash on spans. Set the same key on every
+	// process to correlate traces across them. Nil uses a random key per process.
+	AllyCodeHashKey []byte
+	// CircuitBreaker stops calling comlink for a while after it keeps failing. Nil disables it.
+	CircuitBreaker *httpclient.CircuitBreakerSettings
 }
 
 type Comlink struct {
@@ -41,9 +67,14 @@ type Comlink struct {
 		AccessKey string
 		SecretKey string
 	}
-	HttpClient *httpclient.HTTPClient
-	Ctx        context.Context
-	Wg         *sync.WaitGroup
+	Credentials CredentialsProvider
+	Clock       Clock
+	Logger      *slog.Logger
+	// AllyCodeHashKey keys the ally code hash on spans. Empty uses a random key per process.
+	AllyCodeHashKey []byte
+	HttpClient      *httpclient.HTTPClient
+	Ctx             context.Context
+	Wg              *sync.WaitGroup
 }
 
 func GetComlink(settings *ComlinkSettings) (*Comlink, error) {
@@ -78,14 +109,61 @@ func GetComlink(settings *ComlinkSettings) (*Comlink, error) {
 		comlink.DoHMAC = false
 	}
 
-	comlink.HttpClient = httpclient.Init(comlink.Ctx, comlink.Wg)
+	if settings.Credentials != nil {
+		comlink.Credentials = settings.Credentials
+		comlink.DoHMAC = true
+	}
+
+	comlink.Clock = settings.Clock
+	if comlink.Clock == nil {
+		comlink.Clock = SystemClock
+	}
+
+	client := settings.HTTPClient
+	if client == nil && settings.Transport != nil {
+		client = httpclient.NewClient(settings.Transport)
+	}
+
+	comlink.HttpClient = httpclient.New(comlink.Ctx, comlink.Wg, client)
+
+	if settings.RetryPolicy != nil {
+		comlink.HttpClient.RetryPolicy = *settings.RetryPolicy
+	}
+
+	if settings.RateLimit != nil {
+		comlink.HttpClient.RateLimiter = httpclient.NewRateLimiter(*settings.RateLimit)
+	}
+
+	if settings.CircuitBreaker != nil {
+		breaker := *settings.CircuitBreaker
+		if breaker.Now == nil {
+			breaker.Now = comlink.Clock.Now
+		}
+
+		comlink.HttpClient.CircuitBreaker = httpclient.NewCircuitBreaker(breaker)
+	}
+
+	comlink.HttpClient.Interceptors = settings.Interceptors
+	comlink.HttpClient.AttemptInterceptors = settings.AttemptInterceptors
+	comlink.HttpClient.Logger = settings.Logger
+	comlink.HttpClient.Metrics = settings.Metrics
+	comlink.HttpClient.Tracer = settings.Tracer
+	comlink.Logger = settings.Logger
+	comlink.AllyCodeHashKey = settings.AllyCodeHashKey
 
 	return &comlink, nil
 }
 
 func handleResp(resp *http.Response, err error) (map[string]any, error) {
+	return DecodeResponse[map[string]any](resp, err)
+}
+
+// DecodeResponse decodes a response from any of the Raw functions into T, closing the body when done.
+func DecodeResponse[T any](resp *http.Response, err error) (T, error) {
+	var response T
+
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrUnknownComlink, err)
+		return response, fmt.Errorf("%w: %w", ErrUnknownComlink, err)
 	}
 
 	defer func() {
@@ -94,19 +172,17 @@ func handleResp(resp *http.Response, err error) (map[string]any, error) {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		comlinkError, err := ComlinkErrorHandler(resp)
-		if err != nil {
-			return nil, err
-		}
-
-		return nil, fmt.Errorf("%w: Code: %s Message: %s", ErrBadStatusCode, comlinkError.Code, comlinkError.Message)
+		return response, newAPIError(resp)
 	}
 
-	var response map[string]any
-
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrUnknownComlink, err)
+		if resp.Request != nil {
+			httpclient.LoggerFromContext(resp.Request.Context()).ErrorContext(resp.Request.Context(),
+				"comlink response decode failed", "endpoint", resp.Request.URL.Path, "error_category", "decode", "error", err)
+		}
+
+		return response, fmt.Errorf("%w: %w", ErrUnknownComlink, err)
 	}
 
 	return response, nil
@@ -129,87 +205,217 @@ func ComlinkErrorHandler(resp *http.Response) (ComlinkError, error) {
 }
 
 func (c *Comlink) Enums() (map[string]any, error) {
-	return handleResp(c.EnumsRaw()) //nolint:bodyclose // Handled by handleResp()
+	return c.EnumsCtx(c.Ctx)
+}
+
+func (c *Comlink) EnumsCtx(ctx context.Context) (map[string]any, error) {
+	return handleResp(c.EnumsRawCtx(ctx)) //nolint:bodyclose // Handled by handleResp()
 }
 
 func (c *Comlink) EnumsRaw() (*http.Response, error) {
-	return c.HttpClient.Get(c.ComlinkURL.String() + "/enums")
+	return c.EnumsRawCtx(c.Ctx)
+}
+
+func (c *Comlink) EnumsRawCtx(ctx context.Context) (*http.Response, error) {
+	return c.HttpClient.GetCtx(ctx, c.ComlinkURL.String()+"/enums")
+}
+
+func (c *Comlink) GameData(payload Body) (map[string]any, error) {
+	return c.GameDataCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) GameDataCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.GameDataRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
+}
+
+func (c *Comlink) GameDataRaw(payload Body) (*http.Response, error) {
+	return c.GameDataRawCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) GameDataRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/data", payload)
+}
+
+func (c *Comlink) Metadata(payload Body) (map[string]any, error) {
+	return c.MetadataCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) MetadataCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.MetadataRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
+}
+
+func (c *Comlink) MetadataRaw(payload Body) (*http.Response, error) {
+	return c.MetadataRawCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) MetadataRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/metadata", payload)
+}
+
+func (c *Comlink) Localization(payload Body) (map[string]any, error) {
+	return c.LocalizationCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) LocalizationCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.LocalizationRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
+}
+
+func (c *Comlink) LocalizationRaw(payload Body) (*http.Response, error) {
+	return c.LocalizationRawCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) LocalizationRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/localization", payload)
+}
+
+func (c *Comlink) GetEvents(payload Body) (map[string]any, error) {
+	return c.GetEventsCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) GameData(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.GameDataRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+func (c *Comlink) GetEventsCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.GetEventsRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
 }
 
-func (c *Comlink) GameDataRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/data", payload)
+func (c *Comlink) GetEventsRaw(payload Body) (*http.Response, error) {
+	return c.GetEventsRawCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) Metadata(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.MetadataRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+func (c *Comlink) GetEventsRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/GetEvents", payload)
 }
 
-func (c *Comlink) MetadataRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/metadata", payload)
+func (c *Comlink) Guild(payload Body) (map[string]any, error) {
+	return c.GuildCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) Localization(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.LocalizationRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+func (c *Comlink) GuildCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.GuildRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
 }
 
-func (c *Comlink) LocalizationRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/localization", payload)
+func (c *Comlink) GuildTyped(payload Body) (*GuildResponse, error) {
+	return c.GuildTypedCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) GetEvents(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.GetEventsRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+func (c *Comlink) GuildTypedCtx(ctx context.Context, payload Body) (*GuildResponse, error) {
+	return DecodeResponse[*GuildResponse](c.GuildRawCtx(ctx, payload)) //nolint:bodyclose // Handled by DecodeResponse()
 }
 
-func (c *Comlink) GetEventsRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/GetEvents", payload)
+func (c *Comlink) GuildRaw(payload Body) (*http.Response, error) {
+	return c.GuildRawCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) Guild(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.GuildRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+func (c *Comlink) GuildRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/Guild", payload)
 }
 
-func (c *Comlink) GuildRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/Guild", payload)
+func (c *Comlink) GetGuildLeaderboard(payload Body) (map[string]any, error) {
+	return c.GetGuildLeaderboardCtx(c.Ctx, payload)
 }
-func (c *Comlink) GetGuildLeaderboard(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.GetGuildLeaderboardRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+
+func (c *Comlink) GetGuildLeaderboardCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.GetGuildLeaderboardRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
+}
+
+func (c *Comlink) GetGuildLeaderboardTyped(payload Body) (*GuildLeaderboardResponse, error) {
+	return c.GetGuildLeaderboardTypedCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) GetGuildLeaderboardRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/getGuildLeaderboard", payload)
+func (c *Comlink) GetGuildLeaderboardTypedCtx(ctx context.Context, payload Body) (*GuildLeaderboardResponse, error) {
+	return DecodeResponse[*GuildLeaderboardResponse](c.GetGuildLeaderboardRawCtx(ctx, payload)) //nolint:bodyclose // Handled by DecodeResponse()
 }
-func (c *Comlink) GetGuilds(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.GetGuildsRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+
+func (c *Comlink) GetGuildLeaderboardRaw(payload Body) (*http.Response, error) {
+	return c.GetGuildLeaderboardRawCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) GetGuildLeaderboardRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/getGuildLeaderboard", payload)
+}
+
+func (c *Comlink) GetGuilds(payload Body) (map[string]any, error) {
+	return c.GetGuildsCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) GetGuildsCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.GetGuildsRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
+}
+
+func (c *Comlink) GetGuildsTyped(payload Body) (*GetGuildsResponse, error) {
+	return c.GetGuildsTypedCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) GetGuildsTypedCtx(ctx context.Context, payload Body) (*GetGuildsResponse, error) {
+	return DecodeResponse[*GetGuildsResponse](c.GetGuildsRawCtx(ctx, payload)) //nolint:bodyclose // Handled by DecodeResponse()
+}
+
+func (c *Comlink) GetGuildsRaw(payload Body) (*http.Response, error) {
+	return c.GetGuildsRawCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) GetGuildsRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/getGuilds", payload)
+}
+
+func (c *Comlink) GetLeaderboard(payload Body) (map[string]any, error) {
+	return c.GetLeaderboardCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) GetLeaderboardCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.GetLeaderboardRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
+}
+
+func (c *Comlink) GetLeaderboardRaw(payload Body) (*http.Response, error) {
+	return c.GetLeaderboardRawCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) GetLeaderboardRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/getLeaderboard", payload)
+}
+
+func (c *Comlink) Player(payload Body) (map[string]any, error) {
+	return c.PlayerCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) PlayerCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.PlayerRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
+}
+
+func (c *Comlink) PlayerTyped(payload Body) (*PlayerResponse, error) {
+	return c.PlayerTypedCtx(c.Ctx, payload)
+}
+
+func (c *Comlink) PlayerTypedCtx(ctx context.Context, payload Body) (*PlayerResponse, error) {
+	return DecodeResponse[*PlayerResponse](c.PlayerRawCtx(ctx, payload)) //nolint:bodyclose // Handled by DecodeResponse()
+}
+
+func (c *Comlink) PlayerRaw(payload Body) (*http.Response, error) {
+	return c.PlayerRawCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) GetGuildsRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/getGuilds", payload)
+func (c *Comlink) PlayerRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/player", payload)
 }
 
-func (c *Comlink) GetLeaderboard(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.GetLeaderboardRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+func (c *Comlink) PlayerArena(payload Body) (map[string]any, error) {
+	return c.PlayerArenaCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) GetLeaderboardRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/getLeaderboard", payload)
+func (c *Comlink) PlayerArenaCtx(ctx context.Context, payload Body) (map[string]any, error) {
+	return handleResp(c.PlayerArenaRawCtx(ctx, payload)) //nolint:bodyclose // Handled by handleResp()
 }
 
-func (c *Comlink) Player(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.PlayerRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+func (c *Comlink) PlayerArenaTyped(payload Body) (*PlayerArenaResponse, error) {
+	return c.PlayerArenaTypedCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) PlayerRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/player", payload)
+func (c *Comlink) PlayerArenaTypedCtx(ctx context.Context, payload Body) (*PlayerArenaResponse, error) {
+	return DecodeResponse[*PlayerArenaResponse](c.PlayerArenaRawCtx(ctx, payload)) //nolint:bodyclose // Handled by DecodeResponse()
 }
 
-func (c *Comlink) PlayerArena(payload RequestBody) (map[string]any, error) {
-	return handleResp(c.PlayerArenaRaw(payload)) //nolint:bodyclose // Handled by handleResp()
+func (c *Comlink) PlayerArenaRaw(payload Body) (*http.Response, error) {
+	return c.PlayerArenaRawCtx(c.Ctx, payload)
 }
 
-func (c *Comlink) PlayerArenaRaw(payload RequestBody) (*http.Response, error) {
-	return c.post("/playerArena", payload)
+func (c *Comlink) PlayerArenaRawCtx(ctx context.Context, payload Body) (*http.Response, error) {
+	return c.post(ctx, "/playerArena", payload)
 }
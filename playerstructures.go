@@ -0,0 +1,205 @@
+package ComlinkGo
+
+type PlayerResponse struct {
+	RosterUnit                 []RosterUnit     `json:"rosterUnit"`
+	ProfileStat                []ProfileStat    `json:"profileStat"`
+	PvpProfile                 []PvpProfile     `json:"pvpProfile"`
+	UnlockedPlayerTitle        []PlayerTitle    `json:"unlockedPlayerTitle"`
+	UnlockedPlayerPortrait     []PlayerPortrait `json:"unlockedPlayerPortrait"`
+	SeasonStatus               []SeasonStatus   `json:"seasonStatus"`
+	Datacron                   []Datacron       `json:"datacron"`
+	Name                       string           `json:"name"`
+	Level                      int              `json:"level"`
+	AllyCode                   string           `json:"allyCode"`
+	PlayerId                   string           `json:"playerId"`
+	GuildId                    string           `json:"guildId"`
+	GuildName                  string           `json:"guildName"`
+	GuildLogoBackground        string           `json:"guildLogoBackground"`
+	GuildBannerColor           string           `json:"guildBannerColor"`
+	GuildBannerLogo            string           `json:"guildBannerLogo"`
+	GuildTypeId                string           `json:"guildTypeId"`
+	SelectedPlayerTitle        *PlayerTitle     `json:"selectedPlayerTitle"`
+	SelectedPlayerPortrait     *PlayerPortrait  `json:"selectedPlayerPortrait"`
+	LocalTimeZoneOffsetMinutes int              `json:"localTimeZoneOffsetMinutes"`
+	LastActivityTime           Int64            `json:"lastActivityTime"`
+	LifetimeSeasonScore        Int64            `json:"lifetimeSeasonScore"`
+	PlayerRating               *PlayerRating    `json:"playerRating"`
+}
+
+type PlayerArenaResponse struct {
+	PvpProfile                 []PvpProfile    `json:"pvpProfile"`
+	Name                       string          `json:"name"`
+	Level                      int             `json:"level"`
+	AllyCode                   string          `json:"allyCode"`
+	PlayerId                   string          `json:"playerId"`
+	GuildId                    string          `json:"guildId"`
+	GuildName                  string          `json:"guildName"`
+	GuildLogoBackground        string          `json:"guildLogoBackground"`
+	GuildBannerColor           string          `json:"guildBannerColor"`
+	GuildBannerLogo            string          `json:"guildBannerLogo"`
+	SelectedPlayerTitle        *PlayerTitle    `json:"selectedPlayerTitle"`
+	SelectedPlayerPortrait     *PlayerPortrait `json:"selectedPlayerPortrait"`
+	LocalTimeZoneOffsetMinutes int             `json:"localTimeZoneOffsetMinutes"`
+	LastActivityTime           Int64           `json:"lastActivityTime"`
+	LifetimeSeasonScore        Int64           `json:"lifetimeSeasonScore"`
+	PlayerRating               *PlayerRating   `json:"playerRating"`
+}
+
+type RosterUnit struct {
+	Id                       string            `json:"id"`
+	DefinitionId             string            `json:"definitionId"`
+	CurrentRarity            int               `json:"currentRarity"`
+	CurrentLevel             int               `json:"currentLevel"`
+	CurrentXp                int               `json:"currentXp"`
+	CurrentTier              int               `json:"currentTier"`
+	PromotionRecipeReference string            `json:"promotionRecipeReference"`
+	Relic                    *Relic            `json:"relic"`
+	Skill                    []Skill           `json:"skill"`
+	Equipment                []Equipment       `json:"equipment"`
+	EquippedStatMod          []StatMod         `json:"equippedStatMod"`
+	PurchasedAbilityId       []string          `json:"purchasedAbilityId"`
+	UnitStat                 *UnitStatSnapshot `json:"unitStat"`
+}
+
+type Relic struct {
+	CurrentTier int `json:"currentTier"`
+}
+
+type Skill struct {
+	Id   string `json:"id"`
+	Tier int    `json:"tier"`
+}
+
+type Equipment struct {
+	EquipmentId string `json:"equipmentId"`
+	Slot        int    `json:"slot"`
+}
+
+type StatMod struct {
+	Id            string    `json:"id"`
+	DefinitionId  string    `json:"definitionId"`
+	Level         int       `json:"level"`
+	Tier          int       `json:"tier"`
+	Xp            Int64     `json:"xp"`
+	Locked        bool      `json:"locked"`
+	RerolledCount int       `json:"rerolledCount"`
+	PrimaryStat   *ModStat  `json:"primaryStat"`
+	SecondaryStat []ModStat `json:"secondaryStat"`
+	SellValue     *ItemCost `json:"sellValue"`
+	RemoveCost    *ItemCost `json:"removeCost"`
+	LevelCost     *ItemCost `json:"levelCost"`
+	ConvertedItem *ItemCost `json:"convertedItem"`
+}
+
+type ModStat struct {
+	Stat                *UnitStatValue `json:"stat"`
+	StatRolls           int            `json:"statRolls"`
+	Roll                []Int64        `json:"roll"`
+	UnscaledRollValue   []Int64        `json:"unscaledRollValue"`
+	StatRollerBoundsMin Int64          `json:"statRollerBoundsMin"`
+	StatRollerBoundsMax Int64          `json:"statRollerBoundsMax"`
+}
+
+type UnitStatValue struct {
+	UnitStatId             Enum  `json:"unitStatId"`
+	StatValueDecimal       Int64 `json:"statValueDecimal"`
+	UnscaledDecimalValue   Int64 `json:"unscaledDecimalValue"`
+	UiDisplayOverrideValue Int64 `json:"uiDisplayOverrideValue"`
+	Scalar                 Int64 `json:"scalar"`
+}
+
+type UnitStatSnapshot struct {
+	Stat []UnitStatValue `json:"stat"`
+}
+
+type ItemCost struct {
+	Currency      Enum  `json:"currency"`
+	Quantity      Int64 `json:"quantity"`
+	BonusQuantity Int64 `json:"bonusQuantity"`
+}
+
+type ProfileStat struct {
+	NameKey      string `json:"nameKey"`
+	VersionStamp int    `json:"versionStamp"`
+	Index        int    `json:"index"`
+	Value        Int64  `json:"value"`
+}
+
+type PvpProfile struct {
+	Tab     Enum      `json:"tab"`
+	Rank    int       `json:"rank"`
+	EventId string    `json:"eventId"`
+	Squad   *PvpSquad `json:"squad"`
+}
+
+type PvpSquad struct {
+	Cell []SquadCell `json:"cell"`
+}
+
+type SquadCell struct {
+	UnitId        string `json:"unitId"`
+	UnitDefId     string `json:"unitDefId"`
+	CellIndex     int    `json:"cellIndex"`
+	SquadUnitType Enum   `json:"squadUnitType"`
+}
+
+type PlayerTitle struct {
+	Id             string `json:"id"`
+	ExpirationTime Int64  `json:"expirationTime"`
+}
+
+type PlayerPortrait struct {
+	Id string `json:"id"`
+}
+
+type SeasonStatus struct {
+	SeasonId        string `json:"seasonId"`
+	EventInstanceId string `json:"eventInstanceId"`
+	League          string `json:"league"`
+	Wins            int    `json:"wins"`
+	Losses          int    `json:"losses"`
+	SeasonPoints    int    `json:"seasonPoints"`
+	Rank            int    `json:"rank"`
+	Division        int    `json:"division"`
+	Joined          Int64  `json:"joined"`
+	EndTime         Int64  `json:"endTime"`
+	Remove          bool   `json:"remove"`
+}
+
+type Datacron struct {
+	Id           string          `json:"id"`
+	SetId        int             `json:"setId"`
+	TemplateId   string          `json:"templateId"`
+	Tag          []string        `json:"tag"`
+	Affix        []DatacronAffix `json:"affix"`
+	Focused      bool            `json:"focused"`
+	Locked       bool            `json:"locked"`
+	RerollIndex  int             `json:"rerollIndex"`
+	RerollCount  int             `json:"rerollCount"`
+	RerollOption []DatacronAffix `json:"rerollOption"`
+}
+
+type DatacronAffix struct {
+	TargetRule        string   `json:"targetRule"`
+	AbilityId         string   `json:"abilityId"`
+	StatType          Enum     `json:"statType"`
+	StatValue         Int64    `json:"statValue"`
+	Tag               []string `json:"tag"`
+	RequiredUnitTier  int      `json:"requiredUnitTier"`
+	RequiredRelicTier int      `json:"requiredRelicTier"`
+	ScopeIcon         string   `json:"scopeIcon"`
+}
+
+type PlayerRating struct {
+	PlayerSkillRating *PlayerSkillRating `json:"playerSkillRating"`
+	PlayerRankStatus  *PlayerRankStatus  `json:"playerRankStatus"`
+}
+
+type PlayerSkillRating struct {
+	SkillRating int `json:"skillRating"`
+}
+
+type PlayerRankStatus struct {
+	LeagueId   Enum `json:"leagueId"`
+	DivisionId Enum `json:"divisionId"`
+}
@@ -0,0 +1,552 @@
+package ComlinkGo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+var ErrNoPoolNodes = errors.New("ComlinkPool needs at least one node")
+
+type PoolStrategy int
+
+const (
+	RoundRobin PoolStrategy = iota
+	LeastInFlight
+)
+
+const (
+	DefaultEjectAfter = 3
+	DefaultEjectFor   = 30 * time.Second
+)
+
+type PoolNode struct {
+	ComlinkURL string
+	HMAC       HMACSettings
+	// Credentials takes priority over HMAC, like ComlinkSettings.Credentials.
+	Credentials CredentialsProvider
+}
+
+type PoolSettings struct {
+	Nodes    []PoolNode
+	Strategy PoolStrategy
+	// EjectAfter is how many failures in a row take a node out of rotation for EjectFor. Network
+	// errors and 5xx responses count as failures.
+	EjectAfter int
+	EjectFor   time.Duration
+	// HealthCheckInterval runs CheckHealth in the background until Close. 0 disables it.
+	HealthCheckInterval time.Duration
+}
+
+type NodeStatus struct {
+	ComlinkURL string
+	Healthy    bool
+	InFlight   int
+	Failures   int
+	// Circuit is the state of the node's circuit breaker, CircuitClosed when there is none.
+	Circuit httpclient.CircuitState
+}
+
+// ComlinkPool is a Comlink that spreads requests over several comlink instances. Every attempt
+// picks a node, so a retry goes to a different node than the attempt that failed whenever one is
+// healthy.
+type ComlinkPool struct {
+	*Comlink
+
+	strategy   PoolStrategy
+	ejectAfter int
+	ejectFor   time.Duration
+	cancel     context.CancelFunc
+	health     sync.WaitGroup
+
+	mu    sync.Mutex
+	nodes []*poolNode
+	next  int
+}
+
+type poolNode struct {
+	url          *url.URL
+	credentials  CredentialsProvider
+	inFlight     int
+	failures     int
+	ejectedUntil time.Time
+	breaker      *httpclient.CircuitBreaker
+}
+
+// NewComlinkPool builds the pool on top of settings. ComlinkURL, HMAC and Credentials in settings
+// are ignored in favour of the ones on each node. 502, 503 and 504 are added to the retryable
+// status codes so they fail over, and a retry that goes to an untried healthy node skips the delay.
+// settings.CircuitBreaker gives every node a breaker of its own and nodes with an open breaker are
+// skipped, so pool.HttpClient.CircuitBreaker is nil.
+// The node is picked and the request signed before AttemptInterceptors run, so they see the node's
+// URL and Authorization, and an interceptor that changes the body breaks the signature. Attempt
+// spans and logs are outside the chain, so they carry the endpoint but not the node.
+func NewComlinkPool(settings *ComlinkSettings, poolSettings PoolSettings) (*ComlinkPool, error) {
+	if len(poolSettings.Nodes) == 0 {
+		return nil, ErrNoPoolNodes
+	}
+
+	pool := &ComlinkPool{
+		strategy:   poolSettings.Strategy,
+		ejectAfter: poolSettings.EjectAfter,
+		ejectFor:   poolSettings.EjectFor,
+	}
+
+	if pool.ejectAfter <= 0 {
+		pool.ejectAfter = DefaultEjectAfter
+	}
+
+	if pool.ejectFor <= 0 {
+		pool.ejectFor = DefaultEjectFor
+	}
+
+	for _, node := range poolSettings.Nodes {
+		nodeURL, err := url.ParseRequestURI(node.ComlinkURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedComlinkURL, err)
+		}
+
+		credentials := node.Credentials
+		if credentials == nil && node.HMAC.AccessKey != "" && node.HMAC.SecretKey != "" {
+			credentials = StaticCredentials(node.HMAC)
+		}
+
+		pool.nodes = append(pool.nodes, &poolNode{url: nodeURL, credentials: credentials})
+	}
+
+	shared := *settings
+	shared.ComlinkURL = poolSettings.Nodes[0].ComlinkURL
+	shared.HMAC = HMACSettings{}
+	shared.Credentials = nil
+	shared.Interceptors = append([]httpclient.Interceptor{pool.trackTriedNodes}, settings.Interceptors...)
+	shared.AttemptInterceptors = append([]httpclient.Interceptor{pool.routeAttempt}, settings.AttemptInterceptors...)
+	shared.RetryPolicy = poolRetryPolicy(settings.RetryPolicy)
+	shared.CircuitBreaker = nil
+
+	comlink, err := GetComlink(&shared)
+	if err != nil {
+		return nil, err
+	}
+
+	pool.Comlink = comlink
+
+	if settings.CircuitBreaker != nil {
+		breaker := *settings.CircuitBreaker
+		if breaker.Now == nil {
+			breaker.Now = comlink.Clock.Now
+		}
+
+		for _, node := range pool.nodes {
+			node.breaker = httpclient.NewCircuitBreaker(breaker)
+		}
+	}
+
+	comlink.HttpClient.SkipRetryDelay = pool.skipRetryDelay
+
+	if poolSettings.HealthCheckInterval > 0 {
+		var ctx context.Context
+
+		ctx, pool.cancel = context.WithCancel(comlink.Ctx)
+
+		pool.health.Add(1)
+
+		go pool.healthLoop(ctx, poolSettings.HealthCheckInterval)
+	}
+
+	return pool, nil
+}
+
+func poolRetryPolicy(policy *httpclient.RetryPolicy) *httpclient.RetryPolicy {
+	retryPolicy := httpclient.DefaultRetryPolicy()
+	if policy != nil {
+		retryPolicy = *policy
+	}
+
+	retryPolicy.RetryableStatusCodes = slices.Clone(retryPolicy.RetryableStatusCodes)
+
+	for _, statusCode := range []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		if !slices.Contains(retryPolicy.RetryableStatusCodes, statusCode) {
+			retryPolicy.RetryableStatusCodes = append(retryPolicy.RetryableStatusCodes, statusCode)
+		}
+	}
+
+	return &retryPolicy
+}
+
+// Close stops the background health checks and waits for a running one to finish.
+func (p *ComlinkPool) Close() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+
+	p.health.Wait()
+}
+
+func (p *ComlinkPool) Nodes() []NodeStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.Clock.Now()
+	statuses := make([]NodeStatus, len(p.nodes))
+
+	for i, node := range p.nodes {
+		statuses[i] = NodeStatus{
+			ComlinkURL: node.url.String(),
+			Healthy:    !now.Before(node.ejectedUntil),
+			InFlight:   node.inFlight,
+			Failures:   node.failures,
+		}
+
+		if node.breaker != nil {
+			statuses[i].Circuit = node.breaker.State()
+		}
+	}
+
+	return statuses
+}
+
+// CheckHealth sends /metadata to every node. Nodes that answer come back into rotation and nodes
+// that do not are ejected.
+func (p *ComlinkPool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, node := range p.nodes {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			healthy := p.checkNode(ctx, node)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+
+			if healthy {
+				node.failures = 0
+				node.ejectedUntil = time.Time{}
+			} else {
+				node.failures++
+				node.ejectedUntil = p.Clock.Now().Add(p.ejectFor)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (p *ComlinkPool) checkNode(ctx context.Context, node *poolNode) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, node.url.String()+"/metadata", strings.NewReader("{}"))
+	if err != nil {
+		return false
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	err = p.signFor(node, req, "/metadata")
+	if err != nil {
+		return false
+	}
+
+	resp, err := p.HttpClient.Client.Do(req)
+	if err != nil {
+		return false
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
+func (p *ComlinkPool) healthLoop(ctx context.Context, interval time.Duration) {
+	defer p.health.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.CheckHealth(ctx)
+		}
+	}
+}
+
+type triedNodesKey struct{}
+
+type triedNodes struct {
+	mu    sync.Mutex
+	nodes map[*poolNode]bool
+}
+
+func (p *ComlinkPool) trackTriedNodes(next httpclient.Doer) httpclient.Doer {
+	return func(req *http.Request) (*http.Response, error) {
+		tried := &triedNodes{nodes: make(map[*poolNode]bool)}
+
+		return next(req.WithContext(context.WithValue(req.Context(), triedNodesKey{}, tried)))
+	}
+}
+
+// routeAttempt sends a single attempt to the node picked for it, signed with that node's keys.
+func (p *ComlinkPool) routeAttempt(next httpclient.Doer) httpclient.Doer {
+	return func(req *http.Request) (*http.Response, error) {
+		tried, _ := req.Context().Value(triedNodesKey{}).(*triedNodes)
+
+		node, err := p.pick(tried)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint := strings.TrimPrefix(req.URL.Path, p.ComlinkURL.Path)
+
+		routed := *req.URL
+		routed.Scheme = node.url.Scheme
+		routed.Host = node.url.Host
+		routed.Path = node.url.Path + endpoint
+		req.URL = &routed
+		req.Host = ""
+
+		err = p.signFor(node, req, endpoint)
+		if err != nil {
+			p.release(node)
+
+			if node.breaker != nil {
+				node.breaker.RecordAttempt(req.Context(), nil, err)
+			}
+
+			return nil, fmt.Errorf("%w: %w", ErrInvalidHMAC, err)
+		}
+
+		resp, err := next(req)
+
+		// An attempt that never got to the node, or that the caller gave up on, says nothing about it
+		if httpclient.AttemptReachedComlink(req.Context()) {
+			p.record(node, err == nil && resp.StatusCode < http.StatusInternalServerError)
+		}
+
+		if node.breaker != nil {
+			node.breaker.RecordAttempt(req.Context(), resp, err)
+		}
+
+		// The node is busy until the body has been read
+		return httpclient.ReleaseOnClose(resp, err, func() { p.release(node) })
+	}
+}
+
+// skipRetryDelay is true when the next attempt can go to a node the call has not tried, or when
+// every node's breaker is open so the attempt will be refused anyway.
+func (p *ComlinkPool) skipRetryDelay(ctx context.Context) bool {
+	return p.hasUntriedNode(ctx) || p.allCircuitsOpen()
+}
+
+func (p *ComlinkPool) allCircuitsOpen() bool {
+	for _, node := range p.nodes {
+		if node.breaker == nil || node.breaker.State() != httpclient.CircuitOpen {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasUntriedNode reports whether the call in ctx still has a healthy node it has not tried.
+func (p *ComlinkPool) hasUntriedNode(ctx context.Context) bool {
+	tried, ok := ctx.Value(triedNodesKey{}).(*triedNodes)
+	if !ok {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.Clock.Now()
+
+	for _, node := range p.nodes {
+		if node.available(now) && !tried.has(node) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pick takes a node for an attempt, preferring available nodes the call has not tried. A node
+// whose circuit breaker refuses the attempt is passed over, and the breaker's error is returned
+// when every node refuses.
+func (p *ComlinkPool) pick(tried *triedNodes) (*poolNode, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.Clock.Now()
+
+	var refused error
+
+	for _, preferUntried := range []bool{true, false} {
+		var candidates []int
+
+		for i, node := range p.nodes {
+			if !node.available(now) || preferUntried && tried != nil && tried.has(node) {
+				continue
+			}
+
+			candidates = append(candidates, i)
+		}
+
+		for len(candidates) > 0 {
+			chosen := p.choose(candidates)
+
+			err := p.nodes[chosen].allow()
+			if err == nil {
+				return p.take(p.nodes[chosen], tried), nil
+			}
+
+			refused = err
+			candidates = slices.DeleteFunc(candidates, func(i int) bool { return i == chosen })
+		}
+	}
+
+	if refused != nil {
+		return nil, refused
+	}
+
+	// Every node is out of rotation, so use the one that comes back first rather than failing outright
+	byReturn := slices.SortedStableFunc(slices.Values(p.nodes), func(a, b *poolNode) int {
+		return a.ejectedUntil.Compare(b.ejectedUntil)
+	})
+
+	for _, node := range byReturn {
+		refused = node.allow()
+		if refused == nil {
+			return p.take(node, tried), nil
+		}
+	}
+
+	return nil, refused
+}
+
+// choose returns the index in p.nodes of the candidate the strategy picks.
+func (p *ComlinkPool) choose(candidates []int) int {
+	start := p.next % len(candidates)
+	chosen := candidates[start]
+
+	// Scanning from the round robin position means ties go to the next node in turn
+	if p.strategy == LeastInFlight {
+		for offset := range candidates {
+			i := candidates[(start+offset)%len(candidates)]
+			if p.nodes[i].inFlight < p.nodes[chosen].inFlight {
+				chosen = i
+			}
+		}
+	}
+
+	return chosen
+}
+
+func (p *ComlinkPool) take(node *poolNode, tried *triedNodes) *poolNode {
+	p.next++
+	node.inFlight++
+
+	if tried != nil {
+		tried.add(node)
+	}
+
+	return node
+}
+
+func (p *ComlinkPool) release(node *poolNode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	node.inFlight--
+}
+
+func (p *ComlinkPool) record(node *poolNode, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ok {
+		node.failures = 0
+
+		return
+	}
+
+	node.failures++
+	if node.failures >= p.ejectAfter {
+		node.ejectedUntil = p.Clock.Now().Add(p.ejectFor)
+	}
+}
+
+// signFor replaces any HMAC headers on req with ones made from node's keys. GET requests are not signed.
+func (p *ComlinkPool) signFor(node *poolNode, req *http.Request, endpoint string) error {
+	req.Header.Del("Authorization")
+	req.Header.Del("X-Date")
+
+	if node.credentials == nil || req.Method != http.MethodPost {
+		return nil
+	}
+
+	creds, err := node.credentials.Credentials()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	payload := []byte("{}")
+
+	if req.Body != nil {
+		payload, err = io.ReadAll(req.Body)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(payload))
+	}
+
+	reqTime := strconv.FormatInt(p.Clock.Now().UnixMilli(), 10)
+	signature := computeSignature(creds.SecretKey, reqTime, http.MethodPost, endpoint, payload)
+
+	req.Header.Set("X-Date", reqTime)
+	req.Header.Set("Authorization", fmt.Sprintf("HMAC-SHA256 Credential=%s,Signature=%s", creds.AccessKey, signature))
+
+	return nil
+}
+
+// available reports whether node is in rotation, meaning it is not ejected and its breaker is not open.
+func (n *poolNode) available(now time.Time) bool {
+	return !now.Before(n.ejectedUntil) && (n.breaker == nil || n.breaker.State() != httpclient.CircuitOpen)
+}
+
+func (n *poolNode) allow() error {
+	if n.breaker == nil {
+		return nil
+	}
+
+	return n.breaker.Allow() //nolint:wrapcheck
+}
+
+func (t *triedNodes) has(node *poolNode) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.nodes[node]
+}
+
+func (t *triedNodes) add(node *poolNode) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nodes[node] = true
+}
@@ -2,33 +2,73 @@ package ComlinkGo
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
-	"crypto/md5" //nolint:gosec
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"reflect"
+	"regexp"
 	"strconv"
-	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
 )
 
+var allyCodeField = regexp.MustCompile(`("allyCode"\s*:\s*)"[^"]*"`)
+
+func redactAllyCodes(payload []byte) []byte {
+	return allyCodeField.ReplaceAll(payload, []byte(`$1"REDACTED"`))
+}
+
+// defaultAllyCodeHashKey is random per process. Ally codes are only 9 digits, so a hash without a
+// secret key could be reversed by trying all of them.
+var defaultAllyCodeHashKey = func() []byte {
+	key := make([]byte, 32) //nolint:mnd
+	_, _ = rand.Read(key)
+
+	return key
+}()
+
+// hashAllyCode lets traces group requests for the same player without the ally code itself.
+func (c *Comlink) hashAllyCode(allyCode string) string {
+	if normalized, ok := normalizeAllyCode(allyCode); ok {
+		allyCode = normalized
+	}
+
+	key := c.AllyCodeHashKey
+	if len(key) == 0 {
+		key = defaultAllyCodeHashKey
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(allyCode))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func (c *Comlink) Sign(endpoint string, payload any) (map[string]string, error) {
-	reqTime := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+	creds, err := c.credentials()
+	if err != nil {
+		return nil, err
+	}
+
+	clock := c.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	reqTime := strconv.FormatInt(clock.Now().UnixMilli(), 10)
 
 	headers := map[string]string{
 		"X-Date": reqTime,
 	}
 
-	mac := hmac.New(sha256.New, []byte(c.HMAC.SecretKey))
-	mac.Write([]byte(reqTime))
-	mac.Write([]byte(http.MethodPost))
-	mac.Write([]byte(endpoint))
-
 	var payloadBytes []byte
 
-	var err error
-
 	if payload != nil {
 		payloadBytes, err = json.Marshal(payload)
 		if err != nil {
@@ -38,24 +78,34 @@ func (c *Comlink) Sign(endpoint string, payload any) (map[string]string, error)
 		payloadBytes = []byte("{}")
 	}
 
-	md5Sum := md5.Sum(payloadBytes) //nolint:gosec
-	md5Hex := hex.EncodeToString(md5Sum[:])
+	signature := computeSignature(creds.SecretKey, reqTime, http.MethodPost, endpoint, payloadBytes)
 
-	mac.Write([]byte(md5Hex))
+	headers["Authorization"] = fmt.Sprintf("HMAC-SHA256 Credential=%s,Signature=%s", creds.AccessKey, signature)
 
-	signature := hex.EncodeToString(mac.Sum(nil))
+	return headers, nil
+}
 
-	headers["Authorization"] = fmt.Sprintf("HMAC-SHA256 Credential=%s,Signature=%s", c.HMAC.AccessKey, signature)
+func (c *Comlink) credentials() (HMACSettings, error) {
+	if c.Credentials != nil {
+		return c.Credentials.Credentials() //nolint:wrapcheck
+	}
 
-	return headers, nil
+	return StaticCredentials(c.HMAC).Credentials()
 }
 
-func (c *Comlink) post(endpoint string, payload RequestBody) (*http.Response, error) {
+func (c *Comlink) post(ctx context.Context, endpoint string, payload Body) (*http.Response, error) {
 	var err error
 
 	var headers map[string]string
 
-	convertedPayload := convertRequestBody(payload)
+	convertedPayload := payload.requestBodyPointer()
+
+	if convertedPayload.Payload != nil && convertedPayload.Payload.AllyCode != nil {
+		ctx = httpclient.WithSpanAttributes(ctx, httpclient.Attribute{
+			Key:   httpclient.AttributeAllyCodeHash,
+			Value: c.hashAllyCode(*convertedPayload.Payload.AllyCode),
+		})
+	}
 
 	if c.DoHMAC {
 		headers, err = c.Sign(endpoint, convertedPayload)
@@ -69,9 +119,13 @@ func (c *Comlink) post(endpoint string, payload RequestBody) (*http.Response, er
 		return nil, fmt.Errorf("%w: %w", ErrInvalidBody, err)
 	}
 
+	if c.Logger != nil && c.Logger.Enabled(ctx, slog.LevelDebug) {
+		c.Logger.DebugContext(ctx, "comlink request payload", "endpoint", endpoint, "payload", string(redactAllyCodes(jsonBytes)))
+	}
+
 	body := bytes.NewBuffer(jsonBytes)
 
-	req, err := http.NewRequest(http.MethodPost, c.ComlinkURL.String()+endpoint, body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.ComlinkURL.String()+endpoint, body)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrUnknownComlink, err)
 	}
@@ -85,6 +139,25 @@ func (c *Comlink) post(endpoint string, payload RequestBody) (*http.Response, er
 	return c.HttpClient.DoWithRetry(req)
 }
 
+// Body is accepted by every endpoint. A RequestBody leaves out every zero value, while a
+// RequestBodyPointer sends exactly the fields that are not nil, zero values included.
+type Body interface {
+	requestBodyPointer() RequestBodyPointer
+}
+
+func (r RequestBody) requestBodyPointer() RequestBodyPointer {
+	return convertRequestBody(r)
+}
+
+func (r RequestBodyPointer) requestBodyPointer() RequestBodyPointer {
+	return r
+}
+
+// Ptr is a helper for filling in a RequestBodyPointer, such as StartIndex: ComlinkGo.Ptr(0).
+func Ptr[T any](v T) *T {
+	return &v
+}
+
 func PtrIfNotZero[T comparable](v T) *T {
 	var zero T
 	if v == zero {
@@ -102,97 +175,78 @@ func PtrIfNotEmpty[T any](s []T) *[]T {
 	return &s
 }
 
-//nolint:cyclop,funlen // The complexity is fine
 func convertRequestBody(payload RequestBody) RequestBodyPointer {
 	var response RequestBodyPointer
 
-	if payload.Payload.SearchCriteria.MinMemberCount != 0 ||
-		payload.Payload.SearchCriteria.MaxMemberCount != 0 ||
-		payload.Payload.SearchCriteria.IncludeInviteOnly ||
-		len(payload.Payload.SearchCriteria.RecentTbParticipatedIn) > 0 {
-		response.Payload.SearchCriteria = &SearchCriteriaPointer{}
-		response.Payload.SearchCriteria.MinMemberCount = PtrIfNotZero(payload.Payload.SearchCriteria.MinMemberCount)
-		response.Payload.SearchCriteria.MaxMemberCount = PtrIfNotZero(payload.Payload.SearchCriteria.MaxMemberCount)
-		response.Payload.SearchCriteria.IncludeInviteOnly = PtrIfNotZero(payload.Payload.SearchCriteria.IncludeInviteOnly)
-		response.Payload.SearchCriteria.MinGuildGalacticPower = PtrIfNotZero(payload.Payload.SearchCriteria.MinGuildGalacticPower)
-		response.Payload.SearchCriteria.MaxGuildGalacticPower = PtrIfNotZero(payload.Payload.SearchCriteria.MaxGuildGalacticPower)
-		response.Payload.SearchCriteria.RecentTbParticipatedIn = PtrIfNotEmpty(payload.Payload.SearchCriteria.RecentTbParticipatedIn)
-	}
-
-	if payload.Payload.LeaderboardId.LeaderboardType != 0 ||
-		payload.Payload.LeaderboardId.MonthOffset != 0 {
-		response.Payload.LeaderboardId = &LeaderboardIdPointer{}
-		response.Payload.LeaderboardId.LeaderboardType = PtrIfNotZero(payload.Payload.LeaderboardId.LeaderboardType)
-		response.Payload.LeaderboardId.MonthOffset = PtrIfNotZero(payload.Payload.LeaderboardId.MonthOffset)
-	}
-
-	if payload.Payload.ClientSpecs.Platform != "" ||
-		payload.Payload.ClientSpecs.BundleId != "" ||
-		payload.Payload.ClientSpecs.ExternalVersion != "" ||
-		payload.Payload.ClientSpecs.InternalVersion != "" ||
-		payload.Payload.ClientSpecs.Region != "" {
-		response.Payload.ClientSpecs = &ClientSpecsPointer{}
-		response.Payload.ClientSpecs.Platform = PtrIfNotZero(payload.Payload.ClientSpecs.Platform)
-		response.Payload.ClientSpecs.BundleId = PtrIfNotZero(payload.Payload.ClientSpecs.BundleId)
-		response.Payload.ClientSpecs.ExternalVersion = PtrIfNotZero(payload.Payload.ClientSpecs.ExternalVersion)
-		response.Payload.ClientSpecs.InternalVersion = PtrIfNotZero(payload.Payload.ClientSpecs.InternalVersion)
-		response.Payload.ClientSpecs.Region = PtrIfNotZero(payload.Payload.ClientSpecs.Region)
-	}
-
-	if doesPayloadPointerNeedToExist(payload) {
-		response.Payload = &PayloadPointer{}
-		response.Payload.Version = PtrIfNotZero(payload.Payload.Version)
-		response.Payload.IncludePveUnits = PtrIfNotZero(payload.Payload.IncludePveUnits)
-		response.Payload.DevicePlatform = PtrIfNotZero(payload.Payload.DevicePlatform)
-		response.Payload.RequestSegment = PtrIfNotZero(payload.Payload.RequestSegment)
-		response.Payload.Items = PtrIfNotZero(payload.Payload.Items)
-		response.Payload.Id = PtrIfNotZero(payload.Payload.Id)
-		response.Payload.GuildId = PtrIfNotZero(payload.Payload.GuildId)
-		response.Payload.IncludeRecentGuildActivityInfo = PtrIfNotZero(payload.Payload.IncludeRecentGuildActivityInfo)
-		response.Payload.Count = PtrIfNotZero(payload.Payload.Count)
-		response.Payload.FilterType = PtrIfNotZero(payload.Payload.FilterType)
-		response.Payload.Name = PtrIfNotZero(payload.Payload.Name)
-		response.Payload.StartIndex = PtrIfNotZero(payload.Payload.StartIndex)
-		response.Payload.LeaderboardType = PtrIfNotZero(payload.Payload.LeaderboardType)
-		response.Payload.EventInstanceId = PtrIfNotZero(payload.Payload.EventInstanceId)
-		response.Payload.GroupId = PtrIfNotZero(payload.Payload.GroupId)
-		response.Payload.League = PtrIfNotZero(payload.Payload.League)
-		response.Payload.Division = PtrIfNotZero(payload.Payload.Division)
-		response.Payload.AllyCode = PtrIfNotZero(payload.Payload.AllyCode)
-		response.Payload.PlayerId = PtrIfNotZero(payload.Payload.PlayerId)
-		response.Payload.PlayerDetailsOnly = PtrIfNotZero(payload.Payload.PlayerDetailsOnly)
-	}
-
-	response.Enums = PtrIfNotZero(payload.Enums)
-	response.Unzip = PtrIfNotZero(payload.Unzip)
+	fillPointers(reflect.ValueOf(payload), reflect.ValueOf(&response).Elem())
 
 	return response
 }
 
-//nolint:cyclop,funlen,nolintlint // The complexity is fine. nolintlint is there because of a bug in golangci
-func doesPayloadPointerNeedToExist(payload RequestBody) bool {
-	if payload.Payload.Version != "" ||
-		payload.Payload.IncludePveUnits ||
-		payload.Payload.DevicePlatform != "" ||
-		payload.Payload.RequestSegment != 0 ||
-		payload.Payload.Items != "" ||
-		payload.Payload.Id != "" ||
-		payload.Payload.GuildId != "" ||
-		payload.Payload.IncludeRecentGuildActivityInfo ||
-		payload.Payload.Count != 0 ||
-		payload.Payload.FilterType != 0 ||
-		payload.Payload.Name != "" ||
-		payload.Payload.StartIndex != 0 ||
-		payload.Payload.LeaderboardType != 0 ||
-		payload.Payload.EventInstanceId != "" ||
-		payload.Payload.GroupId != "" ||
-		payload.Payload.League != 0 ||
-		payload.Payload.Division != 0 ||
-		payload.Payload.AllyCode != "" ||
-		payload.Payload.PlayerId != "" ||
-		payload.Payload.PlayerDetailsOnly {
-		return true
-	}
-
-	return false
+// The Pointer structs are checked once when the package loads, so a field added to only one side
+// fails every program and test right away instead of a request later on.
+func init() {
+	err := checkPointerMirror(reflect.TypeFor[RequestBody](), reflect.TypeFor[RequestBodyPointer]())
+	if err != nil {
+		panic("ComlinkGo: " + err.Error())
+	}
+}
+
+// checkPointerMirror makes sure every field of plain has a field with the same name in pointer that
+// fillPointers can set, either a pointer to the same type or, for a nested struct, a pointer to its mirror.
+func checkPointerMirror(plain, pointer reflect.Type) error {
+	for i := range plain.NumField() {
+		field := plain.Field(i)
+
+		match, ok := pointer.FieldByName(field.Name)
+
+		switch {
+		case !ok:
+			return fmt.Errorf("%s.%s has no match in %s", plain.Name(), field.Name, pointer.Name())
+		case field.Type.Kind() == reflect.Struct:
+			if match.Type.Kind() != reflect.Pointer || match.Type.Elem().Kind() != reflect.Struct {
+				return fmt.Errorf("%s.%s is %s, expected a pointer to a struct", pointer.Name(), field.Name, match.Type)
+			}
+
+			err := checkPointerMirror(field.Type, match.Type.Elem())
+			if err != nil {
+				return err
+			}
+		case match.Type != reflect.PointerTo(field.Type):
+			return fmt.Errorf("%s.%s is %s, expected %s", pointer.Name(), field.Name, match.Type, reflect.PointerTo(field.Type))
+		}
+	}
+
+	return nil
+}
+
+// fillPointers sets every field of dst to a pointer to the field of src with the same name, leaving
+// out zero values. Nested structs are only allocated when one of their fields is set, which is what
+// it reports back. init has already checked that the Pointer structs mirror the plain ones.
+func fillPointers(src reflect.Value, dst reflect.Value) bool {
+	set := false
+
+	for i := range src.NumField() {
+		srcField := src.Field(i)
+		dstField := dst.FieldByName(src.Type().Field(i).Name)
+
+		switch {
+		case srcField.Kind() == reflect.Struct:
+			nested := reflect.New(dstField.Type().Elem())
+			if fillPointers(srcField, nested.Elem()) {
+				dstField.Set(nested)
+
+				set = true
+			}
+		case srcField.IsZero(), srcField.Kind() == reflect.Slice && srcField.Len() == 0:
+		default:
+			value := reflect.New(srcField.Type())
+			value.Elem().Set(srcField)
+			dstField.Set(value)
+
+			set = true
+		}
+	}
+
+	return set
 }
@@ -0,0 +1,204 @@
+package ComlinkGo
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingSignature  = errors.New("request is missing HMAC headers")
+	ErrUnknownAccessKey  = errors.New("unknown HMAC access key")
+	ErrSignatureMismatch = errors.New("HMAC signature does not match")
+	ErrClockSkew         = errors.New("request X-Date is outside the allowed clock skew")
+	ErrBodyTooLarge      = errors.New("request body is larger than MaxBodySize")
+)
+
+const (
+	DefaultMaxClockSkew = 5 * time.Minute
+	DefaultMaxBodySize  = 1 << 20
+	authorizationPrefix = "HMAC-SHA256 "
+)
+
+// SignatureVerifier checks the X-Date and Authorization headers that Comlink.Sign produces.
+type SignatureVerifier struct {
+	// AccessKeys maps every accepted access key to its secret key.
+	AccessKeys map[string]string
+	// MaxClockSkew is how far X-Date may be from Now. 0 means DefaultMaxClockSkew, negative disables the check.
+	MaxClockSkew time.Duration
+	// Clock defaults to SystemClock.
+	Clock Clock
+	// MaxBodySize is the most bytes of body that are read to check the signature. 0 means
+	// DefaultMaxBodySize, negative means no limit.
+	MaxBodySize int64
+}
+
+func VerifySignature(req *http.Request, accessKeys map[string]string) error {
+	verifier := SignatureVerifier{AccessKeys: accessKeys}
+
+	return verifier.Verify(req)
+}
+
+// Verify recomputes the signature over X-Date, method, path and the MD5 of the body. The body is
+// restored so handlers after it can still read it.
+func (v *SignatureVerifier) Verify(req *http.Request) error {
+	reqTime := req.Header.Get("X-Date")
+
+	accessKey, signature, ok := parseAuthorization(req.Header.Get("Authorization"))
+	if reqTime == "" || !ok {
+		return ErrMissingSignature
+	}
+
+	secretKey, ok := v.AccessKeys[accessKey]
+	if !ok {
+		return ErrUnknownAccessKey
+	}
+
+	err := v.checkClockSkew(reqTime)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+
+	if req.Body != nil {
+		body, err = v.readBody(req.Body)
+		if err != nil {
+			return err
+		}
+
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+
+	expected := computeSignature(secretKey, reqTime, req.Method, req.URL.Path, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrSignatureMismatch
+	}
+
+	return nil
+}
+
+// Middleware rejects requests that fail Verify with a 401 and a ComlinkError body.
+func (v *SignatureVerifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := v.Verify(r)
+		if err != nil {
+			status := http.StatusUnauthorized
+			if errors.Is(err, ErrBodyTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(ComlinkError{
+				Code:    http.StatusText(status),
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (v *SignatureVerifier) readBody(body io.Reader) ([]byte, error) {
+	maxSize := v.MaxBodySize
+	if maxSize == 0 {
+		maxSize = DefaultMaxBodySize
+	}
+
+	if maxSize > 0 {
+		body = io.LimitReader(body, maxSize+1)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBody, err)
+	}
+
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, ErrBodyTooLarge
+	}
+
+	return data, nil
+}
+
+func (v *SignatureVerifier) checkClockSkew(reqTime string) error {
+	maxSkew := v.MaxClockSkew
+	if maxSkew < 0 {
+		return nil
+	}
+
+	if maxSkew == 0 {
+		maxSkew = DefaultMaxClockSkew
+	}
+
+	millis, err := strconv.ParseInt(reqTime, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid X-Date %q", ErrMissingSignature, reqTime)
+	}
+
+	clock := v.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	skew := clock.Now().Sub(time.UnixMilli(millis))
+	if skew > maxSkew || skew < -maxSkew {
+		return fmt.Errorf("%w: %s", ErrClockSkew, skew)
+	}
+
+	return nil
+}
+
+func parseAuthorization(authorization string) (string, string, bool) {
+	credentials, ok := strings.CutPrefix(authorization, authorizationPrefix)
+	if !ok {
+		return "", "", false
+	}
+
+	credential, signature, ok := strings.Cut(credentials, ",")
+	if !ok {
+		return "", "", false
+	}
+
+	accessKey, ok := strings.CutPrefix(credential, "Credential=")
+	if !ok {
+		return "", "", false
+	}
+
+	signature, ok = strings.CutPrefix(signature, "Signature=")
+	if !ok {
+		return "", "", false
+	}
+
+	return accessKey, signature, true
+}
+
+func computeSignature(secretKey, reqTime, method, endpoint string, body []byte) string {
+	md5Sum := md5.Sum(body) //nolint:gosec
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(reqTime))
+	mac.Write([]byte(method))
+	mac.Write([]byte(endpoint))
+	mac.Write([]byte(hex.EncodeToString(md5Sum[:])))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -9,8 +9,8 @@ type ClientSpecsPointer struct {
 }
 
 type LeaderboardIdPointer struct {
-	LeaderboardType *int `json:"leaderboardType,omitempty"`
-	MonthOffset     *int `json:"monthOffset,omitempty"`
+	LeaderboardType *GuildLeaderboardType `json:"leaderboardType,omitempty"`
+	MonthOffset     *int                  `json:"monthOffset,omitempty"`
 }
 
 type SearchCriteriaPointer struct {
@@ -35,14 +35,14 @@ type PayloadPointer struct {
 	GuildId                        *string                `json:"guildId,omitempty"`
 	IncludeRecentGuildActivityInfo *bool                  `json:"includeRecentGuildActivityInfo,omitempty"`
 	Count                          *int                   `json:"count,omitempty"`
-	FilterType                     *int                   `json:"filterType,omitempty"`
+	FilterType                     *FilterType            `json:"filterType,omitempty"`
 	Name                           *string                `json:"Name,omitempty"`
 	StartIndex                     *int                   `json:"startIndex,omitempty"`
-	LeaderboardType                *int                   `json:"leaderboardType,omitempty"`
+	LeaderboardType                *LeaderboardType       `json:"leaderboardType,omitempty"`
 	EventInstanceId                *string                `json:"eventInstanceId,omitempty"`
 	GroupId                        *string                `json:"groupId,omitempty"`
-	League                         *int                   `json:"league,omitempty"`
-	Division                       *int                   `json:"division,omitempty"`
+	League                         *League                `json:"league,omitempty"`
+	Division                       *Division              `json:"division,omitempty"`
 	AllyCode                       *string                `json:"allyCode,omitempty"`
 	PlayerId                       *string                `json:"playerId,omitempty"`
 	PlayerDetailsOnly              *bool                  `json:"playerDetailsOnly,omitempty"`
@@ -63,8 +63,8 @@ type ClientSpecs struct {
 }
 
 type LeaderboardId struct {
-	LeaderboardType int `json:"leaderboardType,omitempty"`
-	MonthOffset     int `json:"monthOffset,omitempty"`
+	LeaderboardType GuildLeaderboardType `json:"leaderboardType,omitempty"`
+	MonthOffset     int                  `json:"monthOffset,omitempty"`
 }
 
 type SearchCriteria struct {
@@ -77,29 +77,29 @@ type SearchCriteria struct {
 }
 
 type Payload struct {
-	SearchCriteria                 SearchCriteria `json:"searchCriteria,omitempty"`
-	LeaderboardId                  LeaderboardId  `json:"leaderboardId,omitempty"`
-	ClientSpecs                    ClientSpecs    `json:"clientSpecs,omitempty"`
-	Version                        string         `json:"version,omitempty"`
-	IncludePveUnits                bool           `json:"includePveUnits,omitempty"`
-	DevicePlatform                 string         `json:"devicePlatform,omitempty"`
-	RequestSegment                 int            `json:"requestSegment,omitempty"`
-	Items                          string         `json:"items,omitempty"`
-	Id                             string         `json:"id,omitempty"`
-	GuildId                        string         `json:"guildId,omitempty"`
-	IncludeRecentGuildActivityInfo bool           `json:"includeRecentGuildActivityInfo,omitempty"`
-	Count                          int            `json:"count,omitempty"`
-	FilterType                     int            `json:"filterType,omitempty"`
-	Name                           string         `json:"Name,omitempty"`
-	StartIndex                     int            `json:"startIndex,omitempty"`
-	LeaderboardType                int            `json:"leaderboardType,omitempty"`
-	EventInstanceId                string         `json:"eventInstanceId,omitempty"`
-	GroupId                        string         `json:"groupId,omitempty"`
-	League                         int            `json:"league,omitempty"`
-	Division                       int            `json:"division,omitempty"`
-	AllyCode                       string         `json:"allyCode,omitempty"`
-	PlayerId                       string         `json:"playerId,omitempty"`
-	PlayerDetailsOnly              bool           `json:"playerDetailsOnly,omitempty"`
+	SearchCriteria                 SearchCriteria  `json:"searchCriteria,omitempty"`
+	LeaderboardId                  LeaderboardId   `json:"leaderboardId,omitempty"`
+	ClientSpecs                    ClientSpecs     `json:"clientSpecs,omitempty"`
+	Version                        string          `json:"version,omitempty"`
+	IncludePveUnits                bool            `json:"includePveUnits,omitempty"`
+	DevicePlatform                 string          `json:"devicePlatform,omitempty"`
+	RequestSegment                 int             `json:"requestSegment,omitempty"`
+	Items                          string          `json:"items,omitempty"`
+	Id                             string          `json:"id,omitempty"`
+	GuildId                        string          `json:"guildId,omitempty"`
+	IncludeRecentGuildActivityInfo bool            `json:"includeRecentGuildActivityInfo,omitempty"`
+	Count                          int             `json:"count,omitempty"`
+	FilterType                     FilterType      `json:"filterType,omitempty"`
+	Name                           string          `json:"Name,omitempty"`
+	StartIndex                     int             `json:"startIndex,omitempty"`
+	LeaderboardType                LeaderboardType `json:"leaderboardType,omitempty"`
+	EventInstanceId                string          `json:"eventInstanceId,omitempty"`
+	GroupId                        string          `json:"groupId,omitempty"`
+	League                         League          `json:"league,omitempty"`
+	Division                       Division        `json:"division,omitempty"`
+	AllyCode                       string          `json:"allyCode,omitempty"`
+	PlayerId                       string          `json:"playerId,omitempty"`
+	PlayerDetailsOnly              bool            `json:"playerDetailsOnly,omitempty"`
 }
 
 type RequestBody struct {
@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+func TestAPIError(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	comlink := fakeComlink(t, server, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.RetryPolicy = &httpclient.RetryPolicy{MaxAttempts: 1, RetryableStatusCodes: []int{http.StatusBadGateway}}
+	})
+
+	server.SetFailure("/player", comlinktest.Failure{
+		StatusCode: http.StatusBadRequest,
+		Error:      &ComlinkGo.ComlinkError{Code: "PLAYER_NOT_FOUND", Message: "no such player"},
+	})
+
+	_, err := comlink.Player(ComlinkGo.RequestBody{})
+
+	var apiErr *ComlinkGo.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError got %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Endpoint != "/player" ||
+		apiErr.Code != "PLAYER_NOT_FOUND" || apiErr.Message != "no such player" || apiErr.Retryable {
+		t.Errorf("unexpected APIError %+v", apiErr)
+	}
+
+	server.SetFailure("/metadata", comlinktest.Failure{
+		StatusCode: http.StatusBadGateway,
+		Body:       "<html>Bad Gateway</html>",
+	})
+
+	_, err = comlink.Metadata(ComlinkGo.RequestBody{})
+	if !errors.Is(err, ComlinkGo.ErrBadStatusCode) || !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError got %v", err)
+	}
+
+	if apiErr.Message != "<html>Bad Gateway</html>" || string(apiErr.Body) != "<html>Bad Gateway</html>" || !apiErr.Retryable {
+		t.Errorf("unexpected APIError for a non-JSON body %+v", apiErr)
+	}
+
+	// Retryable follows the RetryPolicy, which does not retry a 503 here
+	server.SetFailure("/metadata", comlinktest.Failure{StatusCode: http.StatusServiceUnavailable, Body: "down"})
+
+	_, err = comlink.Metadata(ComlinkGo.RequestBody{})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Retryable {
+		t.Errorf("expected a 503 that is not Retryable got %v", err)
+	}
+}
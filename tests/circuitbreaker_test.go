@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{StatusCode: http.StatusServiceUnavailable, Body: "down"})
+
+	settings := server.Settings()
+	settings.RetryPolicy = &httpclient.RetryPolicy{
+		MaxAttempts:          5,
+		BaseDelay:            time.Hour,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	settings.CircuitBreaker = &httpclient.CircuitBreakerSettings{
+		FailureThreshold: 1,
+		CoolDown:         time.Minute,
+		Now:              func() time.Time { return now },
+	}
+
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// With an hour between retries this only returns quickly because the breaker skips the wait
+	for range 2 {
+		_, err = comlink.Metadata(ComlinkGo.RequestBody{})
+
+		var openErr *httpclient.CircuitOpenError
+		if !errors.Is(err, httpclient.ErrCircuitOpen) || !errors.As(err, &openErr) {
+			t.Fatalf("expected ErrCircuitOpen got %v", err)
+		}
+	}
+
+	if requests := server.Requests("/metadata"); requests != 1 {
+		t.Errorf("expected the breaker to stop after 1 request got %d", requests)
+	}
+
+	if state := comlink.HttpClient.CircuitBreaker.State(); state != httpclient.CircuitOpen {
+		t.Errorf("expected the breaker to be open got %s", state)
+	}
+
+	now = now.Add(time.Minute)
+
+	if state := comlink.HttpClient.CircuitBreaker.State(); state != httpclient.CircuitHalfOpen {
+		t.Errorf("expected the breaker to be half-open got %s", state)
+	}
+
+	server.ClearFailures()
+
+	_, err = comlink.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state := comlink.HttpClient.CircuitBreaker.State(); state != httpclient.CircuitClosed {
+		t.Errorf("expected a successful trial to close the breaker got %s", state)
+	}
+}
+
+func TestCircuitBreakerIgnoresRateLimiterErrors(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	settings := server.Settings()
+	settings.RetryPolicy = &httpclient.RetryPolicy{MaxAttempts: 1}
+	settings.RateLimit = &httpclient.RateLimitSettings{Global: httpclient.RateLimit{RequestsPerSecond: 0.001}}
+	settings.CircuitBreaker = &httpclient.CircuitBreakerSettings{FailureThreshold: 1}
+
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = comlink.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The limiter has no token left, so this call times out before anything is sent
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = comlink.MetadataCtx(ctx, ComlinkGo.RequestBody{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded got %v", err)
+	}
+
+	if requests := server.Requests("/metadata"); requests != 1 {
+		t.Errorf("expected only the first call to reach comlink got %d", requests)
+	}
+
+	if state := comlink.HttpClient.CircuitBreaker.State(); state != httpclient.CircuitClosed {
+		t.Errorf("expected the breaker to stay closed got %s", state)
+	}
+}
+
+func TestCircuitBreakerIgnoresCallerDeadline(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{Latency: 200 * time.Millisecond})
+
+	comlink := fakeComlink(t, server, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.RetryPolicy = &httpclient.RetryPolicy{MaxAttempts: 1}
+		settings.CircuitBreaker = &httpclient.CircuitBreakerSettings{FailureThreshold: 1}
+	})
+
+	// The request is sent, but the caller gives up on it long before comlink answers
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := comlink.MetadataCtx(ctx, ComlinkGo.RequestBody{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded got %v", err)
+	}
+
+	if requests := server.Requests("/metadata"); requests != 1 {
+		t.Errorf("expected the request to reach comlink got %d", requests)
+	}
+
+	if state := comlink.HttpClient.CircuitBreaker.State(); state != httpclient.CircuitClosed {
+		t.Errorf("expected the caller's deadline not to open the breaker got %s", state)
+	}
+}
+
+func TestCircuitBreakerNeedsEveryTrialToSucceed(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	breaker := httpclient.NewCircuitBreaker(httpclient.CircuitBreakerSettings{
+		FailureThreshold: 1,
+		CoolDown:         time.Minute,
+		HalfOpenRequests: 2,
+		Now:              func() time.Time { return now },
+	})
+
+	for _, lastTrial := range []bool{false, true} {
+		breaker.Record(false)
+
+		now = now.Add(time.Minute)
+
+		for range 2 {
+			err := breaker.Allow()
+			if err != nil {
+				t.Fatalf("expected 2 trials to be let through got %v", err)
+			}
+		}
+
+		if err := breaker.Allow(); !errors.Is(err, httpclient.ErrCircuitOpen) {
+			t.Fatalf("expected a third trial to be refused got %v", err)
+		}
+
+		breaker.Record(true)
+
+		if state := breaker.State(); state != httpclient.CircuitHalfOpen {
+			t.Errorf("expected the breaker to wait for the second trial got %s", state)
+		}
+
+		breaker.Record(lastTrial)
+
+		want := httpclient.CircuitOpen
+		if lastTrial {
+			want = httpclient.CircuitClosed
+		}
+
+		if state := breaker.State(); state != want {
+			t.Errorf("expected %s after a second trial that succeeded=%t got %s", want, lastTrial, state)
+		}
+	}
+}
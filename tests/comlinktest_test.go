@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+// testRetryPolicy retries a 503 once without a noticeable delay.
+func testRetryPolicy() *httpclient.RetryPolicy {
+	return &httpclient.RetryPolicy{
+		MaxAttempts:          2,
+		BaseDelay:            time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}
+}
+
+// fakeComlink returns a Comlink for server using testRetryPolicy. Each mutate can change the
+// settings before the Comlink is built.
+func fakeComlink(t *testing.T, server *comlinktest.Server, mutate ...func(*ComlinkGo.ComlinkSettings)) *ComlinkGo.Comlink {
+	t.Helper()
+
+	settings := server.Settings()
+	settings.RetryPolicy = testRetryPolicy()
+
+	for _, m := range mutate {
+		m(settings)
+	}
+
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return comlink
+}
+
+func TestFakeComlinkErrorBody(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{
+		StatusCode: http.StatusBadRequest,
+		Error:      &ComlinkGo.ComlinkError{Code: "BAD", Message: "nope"},
+	})
+
+	_, err := fakeComlink(t, server).Metadata(ComlinkGo.RequestBody{})
+	if !errors.Is(err, ComlinkGo.ErrBadStatusCode) {
+		t.Errorf("expected ErrBadStatusCode got %v", err)
+	}
+}
+
+func TestFakeComlinkRetriesStatusCode(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{StatusCode: http.StatusServiceUnavailable, Body: "down", Times: 1})
+
+	_, err := fakeComlink(t, server).Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if requests := server.Requests("/metadata"); requests != 2 {
+		t.Errorf("expected 2 requests got %d", requests)
+	}
+}
+
+func TestFakeComlinkDroppedConnection(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/player", comlinktest.Failure{DropConnection: true})
+
+	_, err := fakeComlink(t, server).Player(ComlinkGo.RequestBody{})
+	if !errors.Is(err, ComlinkGo.ErrUnknownComlink) {
+		t.Errorf("expected ErrUnknownComlink got %v", err)
+	}
+}
+
+func TestFakeComlinkLatency(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{Latency: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := fakeComlink(t, server).MetadataCtx(ctx, ComlinkGo.RequestBody{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded got %v", err)
+	}
+}
+
+func TestFakeComlinkHMAC(t *testing.T) {
+	server := comlinktest.NewServer(comlinktest.WithHMAC("access", "secret"))
+	defer server.Close()
+
+	_, err := fakeComlink(t, server).Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Errorf("expected a signed request to pass got %v", err)
+	}
+
+	settings := server.Settings()
+	settings.HMAC.SecretKey = "wrong"
+
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = comlink.Metadata(ComlinkGo.RequestBody{})
+	if !errors.Is(err, ComlinkGo.ErrBadStatusCode) {
+		t.Errorf("expected a bad signature to fail got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+)
+
+func TestCanceledContext(t *testing.T) {
+	settings := &ComlinkGo.ComlinkSettings{ComlinkURL: *ComlinkURL}
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = comlink.MetadataCtx(ctx, ComlinkGo.RequestBody{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled got %v", err)
+	}
+}
+
+func TestClientContextCancelsCallContext(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{Latency: time.Minute})
+
+	clientCtx, shutdown := context.WithCancel(context.Background())
+
+	settings := server.Settings()
+	settings.Ctx = clientCtx
+
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, shutdown)
+
+	_, err = comlink.MetadataCtx(context.Background(), ComlinkGo.RequestBody{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled got %v", err)
+	}
+
+	_, errs := ComlinkGo.CollectPlayers(comlink.Players(context.Background(), []string{"813479227", "123456789"}, nil))
+	for id, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled for %s got %v", id, err)
+		}
+	}
+
+	if len(errs) != 2 {
+		t.Errorf("expected both players to fail after shutdown got %v", errs)
+	}
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+)
+
+func TestRequestBodyEncoding(t *testing.T) {
+	var everyField ComlinkGo.RequestBody
+
+	fillEveryField(reflect.ValueOf(&everyField).Elem())
+
+	everyFieldJSON, err := json.Marshal(everyField)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		body     ComlinkGo.RequestBody
+		expected string
+	}{
+		{
+			name:     "empty",
+			body:     ComlinkGo.RequestBody{},
+			expected: `{}`,
+		},
+		{
+			name: "search criteria galactic power only",
+			body: ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{
+				SearchCriteria: ComlinkGo.SearchCriteria{MinGuildGalacticPower: 100, MaxGuildGalacticPower: 200},
+			}},
+			expected: `{"payload":{"searchCriteria":{"minGuildGalacticPower":100,"maxGuildGalacticPower":200}}}`,
+		},
+		{
+			name: "search criteria with other fields",
+			body: ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{
+				FilterType:     ComlinkGo.FilterTypeSearchCriteria,
+				Count:          10,
+				SearchCriteria: ComlinkGo.SearchCriteria{RecentTbParticipatedIn: []string{"t01D"}},
+			}, Enums: true},
+			expected: `{"payload":{"filterType":5,"count":10,"searchCriteria":{"recentTbParticipatedIn":["t01D"]}},"enums":true}`,
+		},
+		{
+			name: "leaderboard id only",
+			body: ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{
+				LeaderboardId: ComlinkGo.LeaderboardId{LeaderboardType: ComlinkGo.GuildLeaderboardTypeGalacticPower, MonthOffset: 1},
+			}},
+			expected: `{"payload":{"leaderboardId":{"leaderboardType":3,"monthOffset":1}}}`,
+		},
+		{
+			name: "client specs only",
+			body: ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{
+				ClientSpecs: ComlinkGo.ClientSpecs{Platform: "Android"},
+			}},
+			expected: `{"payload":{"clientSpecs":{"platform":"Android"}}}`,
+		},
+		{
+			name: "empty slice is left out",
+			body: ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{
+				SearchCriteria: ComlinkGo.SearchCriteria{RecentTbParticipatedIn: []string{}},
+			}, Unzip: true},
+			expected: `{"unzip":true}`,
+		},
+		{
+			name:     "every field",
+			body:     everyField,
+			expected: string(everyFieldJSON),
+		},
+	}
+
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	comlink := fakeComlink(t, server)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := comlink.Metadata(test.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var sent, expected any
+
+			err = json.Unmarshal(server.LastBody("/metadata"), &sent)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = json.Unmarshal([]byte(test.expected), &expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(sent, expected) {
+				t.Errorf("expected %s got %s", test.expected, server.LastBody("/metadata"))
+			}
+		})
+	}
+}
+
+func fillEveryField(value reflect.Value) {
+	switch value.Kind() { //nolint:exhaustive
+	case reflect.Struct:
+		for i := range value.NumField() {
+			fillEveryField(value.Field(i))
+		}
+	case reflect.String:
+		value.SetString("x")
+	case reflect.Int:
+		value.SetInt(1)
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Slice:
+		value.Set(reflect.MakeSlice(value.Type(), 1, 1))
+		fillEveryField(value.Index(0))
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+)
+
+func TestSignWithClock(t *testing.T) {
+	fixed := time.UnixMilli(1700000000000)
+
+	comlink, err := ComlinkGo.GetComlink(&ComlinkGo.ComlinkSettings{
+		ComlinkURL:  "http://localhost:3000",
+		Credentials: ComlinkGo.StaticCredentials{AccessKey: "access", SecretKey: "secret"},
+		Clock:       ComlinkGo.ClockFunc(func() time.Time { return fixed }),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := comlink.Sign("/player", map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := comlink.Sign("/player", map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first["X-Date"] != "1700000000000" {
+		t.Errorf("expected X-Date from the clock got %s", first["X-Date"])
+	}
+
+	if first["Authorization"] != second["Authorization"] {
+		t.Error("expected identical signatures from a fixed clock")
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("TEST_ACCESS", "")
+	t.Setenv("TEST_SECRET", "")
+
+	provider := ComlinkGo.EnvCredentials{AccessKeyEnv: "TEST_ACCESS", SecretKeyEnv: "TEST_SECRET"}
+
+	_, err := provider.Credentials()
+	if !errors.Is(err, ComlinkGo.ErrMissingCredentials) {
+		t.Errorf("expected ErrMissingCredentials got %v", err)
+	}
+
+	t.Setenv("TEST_ACCESS", "access")
+	t.Setenv("TEST_SECRET", "secret")
+
+	creds, err := provider.Credentials()
+	if err != nil || creds.AccessKey != "access" || creds.SecretKey != "secret" {
+		t.Errorf("unexpected credentials %+v %v", creds, err)
+	}
+}
+
+func TestFileCredentialsRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hmac.json")
+
+	writeKeys := func(secret string, modTime time.Time) {
+		err := os.WriteFile(path, []byte(`{"accessKey": "access", "secretKey": "`+secret+`"}`), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.Chtimes(path, modTime, modTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeKeys("old", time.Now().Add(-time.Hour))
+
+	server := comlinktest.NewServer(comlinktest.WithHMAC("access", "new"))
+	defer server.Close()
+
+	settings := server.Settings()
+	settings.HMAC = ComlinkGo.HMACSettings{}
+	settings.Credentials = ComlinkGo.NewFileCredentials(path)
+
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = comlink.Metadata(ComlinkGo.RequestBody{})
+	if !errors.Is(err, ComlinkGo.ErrBadStatusCode) {
+		t.Errorf("expected the old key to be rejected got %v", err)
+	}
+
+	writeKeys("new", time.Now())
+
+	_, err = comlink.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Errorf("expected the rotated key to be accepted got %v", err)
+	}
+}
+
+func TestChangingHMACAfterGetComlink(t *testing.T) {
+	server := comlinktest.NewServer(comlinktest.WithHMAC("rotated", "rotated-secret"))
+	defer server.Close()
+
+	comlink, err := ComlinkGo.GetComlink(&ComlinkGo.ComlinkSettings{
+		ComlinkURL: server.URL,
+		HMAC:       ComlinkGo.HMACSettings{AccessKey: "old", SecretKey: "old-secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comlink.HMAC.AccessKey = "rotated"
+	comlink.HMAC.SecretKey = "rotated-secret"
+
+	_, err = comlink.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Errorf("expected the changed HMAC keys to be used got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+func TestSendValidatesBeforeRequest(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	comlink := fakeComlink(t, server)
+
+	invalid := []ComlinkGo.Request{
+		ComlinkGo.PlayerRequest{},
+		ComlinkGo.PlayerRequest{AllyCode: "123", PlayerId: "abc"},
+		ComlinkGo.PlayerArenaRequest{AllyCode: "12345"},
+		ComlinkGo.GuildRequest{},
+		ComlinkGo.GetGuildsRequest{Name: "x", SearchCriteria: ComlinkGo.SearchCriteria{MinMemberCount: 1}},
+		ComlinkGo.LeaderboardRequest{LeaderboardType: ComlinkGo.LeaderboardTypeLeagueDivision, League: ComlinkGo.LeagueKyber},
+		ComlinkGo.GuildLeaderboardRequest{},
+		ComlinkGo.GameDataRequest{},
+		ComlinkGo.LocalizationRequest{},
+	}
+
+	for _, req := range invalid {
+		_, err := comlink.Send(context.Background(), req)
+		if !errors.Is(err, ComlinkGo.ErrInvalidRequest) {
+			t.Errorf("%T: expected ErrInvalidRequest got %v", req, err)
+		}
+
+		if requests := server.Requests(req.Endpoint()); requests != 0 {
+			t.Errorf("%T: expected no HTTP request got %d", req, requests)
+		}
+	}
+
+	player, err := ComlinkGo.DecodeResponse[*ComlinkGo.PlayerResponse](
+		comlink.SendRaw(context.Background(), ComlinkGo.PlayerRequest{AllyCode: "813-479-227"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if player.AllyCode != "813479227" {
+		t.Errorf("expected 813479227 got %s", player.AllyCode)
+	}
+}
+
+func TestSendKeepsRequiredZeroValues(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	comlink := fakeComlink(t, server)
+
+	tests := []struct {
+		req      ComlinkGo.Request
+		expected string
+	}{
+		{
+			req: ComlinkGo.GuildLeaderboardRequest{
+				LeaderboardId: ComlinkGo.LeaderboardIdPointer{LeaderboardType: ComlinkGo.Ptr(ComlinkGo.GuildLeaderboardTypeAllTimeRaid)},
+			},
+			expected: `{"payload":{"leaderboardId":{"leaderboardType":0,"monthOffset":0}},"enums":false}`,
+		},
+		{
+			req:      ComlinkGo.GetGuildsRequest{SearchCriteria: ComlinkGo.SearchCriteria{MinMemberCount: 10}},
+			expected: `{"payload":{"searchCriteria":{"minMemberCount":10,"includeInviteOnly":false},"filterType":5,"startIndex":0},"enums":false}`,
+		},
+		{
+			req:      ComlinkGo.GameDataRequest{Version: "v1"},
+			expected: `{"payload":{"version":"v1","includePveUnits":false,"requestSegment":0},"enums":false}`,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := comlink.Send(context.Background(), test.req)
+		if err != nil {
+			t.Fatalf("%T: %v", test.req, err)
+		}
+
+		var sent, expected any
+
+		err = json.Unmarshal(server.LastBody(test.req.Endpoint()), &sent)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = json.Unmarshal([]byte(test.expected), &expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(sent, expected) {
+			t.Errorf("%T: expected %s got %s", test.req, test.expected, server.LastBody(test.req.Endpoint()))
+		}
+	}
+}
+
+func TestRequestEndpointsMatchEndpointMethods(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	var mu sync.Mutex
+
+	var paths []string
+
+	comlink := fakeComlink(t, server, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.AttemptInterceptors = []httpclient.Interceptor{
+			func(next httpclient.Doer) httpclient.Doer {
+				return func(req *http.Request) (*http.Response, error) {
+					mu.Lock()
+					paths = append(paths, req.URL.Path)
+					mu.Unlock()
+
+					return next(req)
+				}
+			},
+		}
+	})
+
+	guild := ComlinkGo.GuildRequest{GuildId: "fakeGuildId"}
+	events := ComlinkGo.GetEventsRequest{}
+
+	_, guildErr := comlink.Guild(ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{GuildId: "fakeGuildId"}})
+	_, guildSendErr := comlink.Send(context.Background(), guild)
+	_, eventsErr := comlink.GetEvents(ComlinkGo.RequestBody{})
+	_, eventsSendErr := comlink.Send(context.Background(), events)
+
+	err := errors.Join(guildErr, guildSendErr, eventsErr, eventsSendErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{guild.Endpoint(), guild.Endpoint(), events.Endpoint(), events.Endpoint()}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v got %v", expected, paths)
+	}
+}
@@ -0,0 +1,21 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+)
+
+func TestGeneratedEnums(t *testing.T) {
+	if ComlinkGo.LeagueKyber.String() != "KYBER" {
+		t.Errorf("expected KYBER got %s", ComlinkGo.LeagueKyber)
+	}
+
+	if ComlinkGo.Division1 != 25 || ComlinkGo.Division1.String() != "DIVISION_1" {
+		t.Errorf("unexpected Division1 %d %s", ComlinkGo.Division1, ComlinkGo.Division1)
+	}
+
+	if got := ComlinkGo.FilterType(99).String(); got != "FilterType(99)" {
+		t.Errorf("expected FilterType(99) got %s", got)
+	}
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runEnumgen(t *testing.T, input string, args ...string) (string, error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "enums.json")
+
+	err := os.WriteFile(in, []byte(input), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", append([]string{"run", "./cmd/enumgen", "-in", in, "-out", filepath.Join(dir, "enums_gen.go")}, args...)...)
+	cmd.Dir = ".."
+
+	output, err := cmd.CombinedOutput()
+
+	return string(output), err
+}
+
+func TestEnumgenRejectsDuplicateIdentifiers(t *testing.T) {
+	output, err := runEnumgen(t, `{"Foo": {"BAR_BAZ": 1, "BAR-BAZ": 2}}`)
+	if err == nil || !strings.Contains(output, "would both generate FooBarBaz") {
+		t.Errorf("expected enumgen to fail on FooBarBaz got %v\n%s", err, output)
+	}
+
+	output, err = runEnumgen(t, `{"Foo": {"BAR": 1}, "FooBar": {"NONE": 0}, "Other": {"BAR": 1}}`, "-only", "Foo,Other=Foo")
+	if err == nil || !strings.Contains(output, "would both generate Foo") {
+		t.Errorf("expected enumgen to fail on two enums named Foo got %v\n%s", err, output)
+	}
+
+	_, err = runEnumgen(t, `{"Foo": {"BAR": 1}, "Unused": {"BAR-BAZ": 1, "BAR_BAZ": 2}}`, "-only", "Foo")
+	if err != nil {
+		t.Errorf("expected enums outside -only to be ignored got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+)
+
+func TestEnumRegistry(t *testing.T) {
+	comlink, err := ComlinkGo.GetComlink(&ComlinkGo.ComlinkSettings{ComlinkURL: *ComlinkURL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := comlink.LoadEnumRegistry(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, ok := registry.Name("CombatType", 2)
+	if !ok || name != "SHIP" {
+		t.Errorf("expected SHIP got %q", name)
+	}
+
+	named, err := registry.ToNames(map[string]any{"units": []any{map[string]any{"combatType": float64(1)}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unit := named.(map[string]any)["units"].([]any)[0].(map[string]any)
+	if unit["combatType"] != "CHARACTER" {
+		t.Errorf("expected CHARACTER got %v", unit["combatType"])
+	}
+
+	numbered, err := registry.ToNumbers(named)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unit = numbered.(map[string]any)["units"].([]any)[0].(map[string]any)
+	if unit["combatType"] != 1 {
+		t.Errorf("expected 1 got %v", unit["combatType"])
+	}
+
+	guild := &ComlinkGo.GuildResponse{
+		Guild: ComlinkGo.Guild{
+			Member: []ComlinkGo.GuildMember{{MemberLevel: ComlinkGo.Enum{Number: 4}}},
+		},
+	}
+
+	registry.ResolveEnums(guild)
+
+	if level := guild.Guild.Member[0].MemberLevel; level.Name != "GUILDMEMBERLEVELLEADER" || level.Number != 4 {
+		t.Errorf("expected GUILDMEMBERLEVELLEADER got %+v", level)
+	}
+}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+)
+
+func TestGameDataCache(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	stale := filepath.Join(dir, "stale-version")
+
+	err := os.Mkdir(stale, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err := ComlinkGo.NewGameDataCache(fakeComlink(t, server), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := ComlinkGo.RequestBody{
+		Payload: ComlinkGo.Payload{
+			RequestSegment: 1,
+		},
+	}
+
+	first, err := cache.GameDataRaw(context.Background(), payload)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("expected the stale version to be removed")
+	}
+
+	second, err := cache.GameDataRaw(context.Background(), payload)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if string(first) != string(second) {
+		t.Error("expected the cached response to match the first response")
+	}
+
+	if requests := server.Requests("/data"); requests != 1 {
+		t.Errorf("expected the second call to be served from disk got %d /data requests", requests)
+	}
+}
+
+func TestGameDataCacheConcurrentDownloads(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/data", comlinktest.Failure{Latency: 500 * time.Millisecond})
+
+	cache, err := ComlinkGo.NewGameDataCache(fakeComlink(t, server), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+
+	start := time.Now()
+
+	// Three callers share segment 1 and one asks for segment 2 at the same time
+	for _, segment := range []int{1, 1, 1, 2} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := cache.GameDataRaw(context.Background(), ComlinkGo.RequestBody{
+				Payload: ComlinkGo.Payload{Version: "v1", RequestSegment: segment},
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if requests := server.Requests("/data"); requests != 2 {
+		t.Errorf("expected one /data request per segment got %d", requests)
+	}
+
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Errorf("expected the two segments to download at the same time but it took %s", elapsed)
+	}
+}
+
+func TestGameDataCacheAcceptsRequestBodyPointer(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	cache, err := ComlinkGo.NewGameDataCache(fakeComlink(t, server), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pointer := ComlinkGo.RequestBodyPointer{
+		Payload: &ComlinkGo.PayloadPointer{RequestSegment: ComlinkGo.Ptr(0)},
+	}
+
+	_, err = cache.GameDataRaw(context.Background(), pointer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pointer.Payload.Version != nil {
+		t.Error("expected the caller's payload to be left alone")
+	}
+
+	if !strings.Contains(string(server.LastBody("/data")), `"requestSegment":0`) {
+		t.Errorf("expected requestSegment 0 on the wire got %s", server.LastBody("/data"))
+	}
+
+	// The same request as a RequestBody should come from the cache
+	_, err = cache.GameDataRaw(context.Background(), ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests := server.Requests("/data"); requests != 1 {
+		t.Errorf("expected 1 /data request got %d", requests)
+	}
+}
+
+func TestGameDataCacheSharedDownloadOutlivesFirstCaller(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/data", comlinktest.Failure{Latency: 200 * time.Millisecond})
+
+	cache, err := ComlinkGo.NewGameDataCache(fakeComlink(t, server), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{Version: "v1", RequestSegment: 1}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+
+	go func() {
+		_, err := cache.GameDataRaw(ctx, payload)
+		leaderErr <- err
+	}()
+
+	// Wait for the first caller's download to reach comlink before joining it
+	for server.Requests("/data") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiterErr := make(chan error, 1)
+
+	go func() {
+		_, err := cache.GameDataRaw(context.Background(), payload)
+		waiterErr <- err
+	}()
+
+	cancel()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the first caller to get context.Canceled got %v", err)
+	}
+
+	if err := <-waiterErr; err != nil {
+		t.Errorf("expected the second caller to get the download got %v", err)
+	}
+
+	if requests := server.Requests("/data"); requests != 1 {
+		t.Errorf("expected 1 /data request got %d", requests)
+	}
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+)
+
+func TestGuildTyped(t *testing.T) {
+	settings := &ComlinkGo.ComlinkSettings{ComlinkURL: *ComlinkURL}
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	player, err := comlink.PlayerTyped(ComlinkGo.RequestBody{
+		Payload: ComlinkGo.Payload{
+			AllyCode: *AllyCode,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if player.GuildId == "" {
+		t.Skip("test player is not in a guild")
+	}
+
+	guild, err := comlink.GuildTyped(ComlinkGo.RequestBody{
+		Payload: ComlinkGo.Payload{
+			GuildId:                        player.GuildId,
+			IncludeRecentGuildActivityInfo: true,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if guild.Guild.Profile.Id != player.GuildId {
+		t.Errorf("expected guild id %s got %s", player.GuildId, guild.Guild.Profile.Id)
+	}
+
+	if len(guild.Guild.Member) == 0 {
+		t.Error("guild had no members")
+	}
+}
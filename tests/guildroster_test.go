@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+)
+
+func TestGuildWithRosters(t *testing.T) {
+	settings := &ComlinkGo.ComlinkSettings{ComlinkURL: *ComlinkURL}
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	player, err := comlink.PlayerTyped(ComlinkGo.RequestBody{
+		Payload: ComlinkGo.Payload{
+			AllyCode: *AllyCode,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if player.GuildId == "" {
+		t.Skip("test player is not in a guild")
+	}
+
+	roster, err := comlink.GuildWithRosters(context.Background(), player.GuildId, &ComlinkGo.PlayersOptions{Workers: 5})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(roster.Players)+len(roster.Errors) != len(roster.Guild.Guild.Member) {
+		t.Errorf("expected a player or error for all %d members, got %d players and %d errors",
+			len(roster.Guild.Guild.Member), len(roster.Players), len(roster.Errors))
+	}
+
+	if _, ok := roster.Players[player.PlayerId]; !ok {
+		t.Errorf("expected %s to be in the roster", player.PlayerId)
+	}
+}
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+
+	named := func(name string) httpclient.Interceptor {
+		return func(next httpclient.Doer) httpclient.Doer {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+">")
+				resp, err := next(req)
+				order = append(order, "<"+name)
+
+				return resp, err
+			}
+		}
+	}
+
+	final := func(_ *http.Request) (*http.Response, error) {
+		order = append(order, "final")
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}
+
+	_, err := httpclient.Chain(final, named("first"), named("second"))(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"first>", "second>", "final", "<second", "<first"}; !slices.Equal(order, expected) {
+		t.Errorf("expected %v got %v", expected, order)
+	}
+}
+
+func TestInterceptorsWrapCallsAndAttempts(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{DropConnection: true, Times: 1})
+
+	var mu sync.Mutex
+
+	var calls, attempts, userAgents []string
+
+	record := func(list *[]string, value string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		*list = append(*list, value)
+	}
+
+	comlink := fakeComlink(t, server, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.Interceptors = []httpclient.Interceptor{
+			httpclient.UserAgentInterceptor("ComlinkGo-test"),
+			httpclient.ObserveInterceptor(func(_ *http.Request, resp *http.Response, err error, _ time.Duration) {
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				record(&calls, resp.Status)
+			}),
+		}
+		settings.AttemptInterceptors = []httpclient.Interceptor{
+			func(next httpclient.Doer) httpclient.Doer {
+				return func(req *http.Request) (*http.Response, error) {
+					record(&userAgents, req.Header.Get("User-Agent"))
+
+					resp, err := next(req)
+
+					outcome := "error"
+					if err == nil {
+						outcome = resp.Status
+					}
+
+					record(&attempts, outcome+"#"+strconv.Itoa(httpclient.AttemptFromContext(req.Context())))
+
+					return resp, err
+				}
+			},
+		}
+	})
+
+	_, err := comlink.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(calls, []string{"200 OK"}) {
+		t.Errorf("expected the call interceptor to see only the final response got %v", calls)
+	}
+
+	if !slices.Equal(attempts, []string{"error#1", "200 OK#2"}) {
+		t.Errorf("expected the attempt interceptor to see both attempts got %v", attempts)
+	}
+
+	if !slices.Equal(userAgents, []string{"ComlinkGo-test", "ComlinkGo-test"}) {
+		t.Errorf("expected the user agent on every attempt got %v", userAgents)
+	}
+}
+
+func TestInterceptorCanSkipTheRequest(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	comlink := fakeComlink(t, server, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.Interceptors = []httpclient.Interceptor{
+			func(_ httpclient.Doer) httpclient.Doer {
+				return func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"cached":true}`)),
+						Request:    req,
+					}, nil
+				}
+			},
+		}
+	})
+
+	metadata, err := comlink.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata["cached"] != true {
+		t.Errorf("expected the response from the interceptor got %v", metadata)
+	}
+
+	if requests := server.Requests("/metadata"); requests != 0 {
+		t.Errorf("expected no request to reach comlink got %d", requests)
+	}
+}
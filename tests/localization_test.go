@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+)
+
+const locFile = "# comment\nUNIT_NAME|Darth Vader\nPIPE_TEST|a|b\n"
+
+func TestParseLocalizationResponse(t *testing.T) {
+	var buf bytes.Buffer
+
+	zipWriter := zip.NewWriter(&buf)
+
+	file, err := zipWriter.Create("Loc_ENG_US.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = file.Write([]byte(locFile))
+
+	err = zipWriter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	responses := map[string]map[string]any{
+		"unzipped": {"Loc_ENG_US.txt": locFile, "Key_Mapping.txt": "ignored"},
+		"zipped":   {"localizationBundle": base64.StdEncoding.EncodeToString(buf.Bytes())},
+	}
+
+	for name, response := range responses {
+		bundle, err := ComlinkGo.ParseLocalizationResponse(response)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		if len(bundle) != 1 {
+			t.Errorf("%s: expected 1 language got %d", name, len(bundle))
+		}
+
+		if bundle["ENG_US"]["UNIT_NAME"] != "Darth Vader" {
+			t.Errorf("%s: expected Darth Vader got %q", name, bundle["ENG_US"]["UNIT_NAME"])
+		}
+
+		if bundle["ENG_US"]["PIPE_TEST"] != "a|b" {
+			t.Errorf("%s: expected a|b got %q", name, bundle["ENG_US"]["PIPE_TEST"])
+		}
+	}
+}
+
+func TestLatestLocalization(t *testing.T) {
+	settings := &ComlinkGo.ComlinkSettings{ComlinkURL: *ComlinkURL}
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	bundle, err := comlink.LatestLocalization(context.Background(), true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(bundle["ENG_US"]) == 0 {
+		t.Error("expected ENG_US to have entries")
+	}
+}
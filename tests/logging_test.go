@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+func loggingComlink(t *testing.T, server *comlinktest.Server, level slog.Level, mutate ...func(*ComlinkGo.ComlinkSettings)) (*ComlinkGo.Comlink, *bytes.Buffer) {
+	t.Helper()
+
+	var logs bytes.Buffer
+
+	mutate = append(mutate, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: level}))
+	})
+
+	return fakeComlink(t, server, mutate...), &logs
+}
+
+func TestLoggingDebugRedactsAllyCodesAndHeaders(t *testing.T) {
+	server := comlinktest.NewServer(comlinktest.WithHMAC("log-access", "log-secret"))
+	defer server.Close()
+
+	now := time.Now()
+
+	comlink, logs := loggingComlink(t, server, slog.LevelDebug, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.Clock = ComlinkGo.ClockFunc(func() time.Time { return now })
+	})
+
+	_, err := comlink.Send(context.Background(), ComlinkGo.PlayerRequest{AllyCode: "813-479-227"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := logs.String()
+
+	for _, expected := range []string{
+		"comlink request started",
+		"comlink request payload",
+		`allyCode\":\"REDACTED\"`,
+		"comlink attempt finished",
+		"attempt=1",
+		"comlink request finished",
+		"attempts=1",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in the logs got\n%s", expected, output)
+		}
+	}
+
+	// Headers are never logged, so neither the HMAC credentials nor X-Date can show up
+	for _, secret := range []string{"813479227", "log-access", "HMAC-SHA256", strconv.FormatInt(now.UnixMilli(), 10)} {
+		if strings.Contains(output, secret) {
+			t.Errorf("%q was logged\n%s", secret, output)
+		}
+	}
+}
+
+func TestLoggingRetryCategory(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{StatusCode: http.StatusTooManyRequests, Times: 1})
+
+	comlink, logs := loggingComlink(t, server, slog.LevelWarn, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.RetryPolicy = &httpclient.RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusTooManyRequests}}
+	})
+
+	_, err := comlink.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output := logs.String(); !strings.Contains(output, "comlink retrying request") || !strings.Contains(output, "error_category=rate_limited") {
+		t.Errorf("expected a rate_limited retry line got\n%s", output)
+	}
+}
+
+func TestLoggingInfo(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/guild", comlinktest.Failure{StatusCode: http.StatusBadRequest, Body: "bad guild"})
+
+	comlink, logs := loggingComlink(t, server, slog.LevelInfo)
+
+	_, err := comlink.Send(context.Background(), ComlinkGo.PlayerRequest{AllyCode: "813479227"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = comlink.Send(context.Background(), ComlinkGo.GuildRequest{GuildId: "fakeGuildId"})
+	if err == nil {
+		t.Fatal("expected the /Guild call to fail")
+	}
+
+	output := logs.String()
+
+	if !strings.Contains(output, "comlink request finished") || !strings.Contains(output, "endpoint=/player") {
+		t.Errorf("expected a finished line for /player got\n%s", output)
+	}
+
+	if !strings.Contains(output, "level=ERROR") || !strings.Contains(output, "error_category=client_error") {
+		t.Errorf("expected the failed /Guild call at error level got\n%s", output)
+	}
+
+	if strings.Contains(output, "payload") || strings.Contains(output, "attempt finished") {
+		t.Errorf("debug lines were logged at info level\n%s", output)
+	}
+}
@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+)
+
+// Without -comlink the tests run against the in-memory fake from comlinktest.
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	if *ComlinkURL == "" {
+		server := comlinktest.NewServer()
+		*ComlinkURL = server.URL
+
+		code := m.Run()
+
+		server.Close()
+		os.Exit(code)
+	}
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+func scrape(t *testing.T, metrics *httpclient.PrometheusMetrics) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected Content-Type %s", contentType)
+	}
+
+	return recorder.Body.String()
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/player", comlinktest.Failure{DropConnection: true, Times: 1})
+	server.SetFailure("/guild", comlinktest.Failure{StatusCode: http.StatusBadRequest, Body: "bad guild"})
+
+	metrics := httpclient.NewPrometheusMetrics()
+
+	comlink := fakeComlink(t, server, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.Metrics = metrics
+	})
+
+	for range 2 {
+		_, err := comlink.Metadata(ComlinkGo.RequestBody{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := comlink.Send(context.Background(), ComlinkGo.PlayerRequest{AllyCode: "813479227"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = comlink.Send(context.Background(), ComlinkGo.GuildRequest{GuildId: "fakeGuildId"})
+
+	output := scrape(t, metrics)
+
+	for _, expected := range []string{
+		"# TYPE comlink_requests_total counter",
+		`comlink_requests_total{endpoint="/metadata",code="200"} 2`,
+		`comlink_requests_total{endpoint="/Guild",code="400"} 1`,
+		`comlink_retries_total{endpoint="/player",code="0"} 1`,
+		"# TYPE comlink_request_duration_seconds histogram",
+		`comlink_request_duration_seconds_count{endpoint="/metadata"} 2`,
+		"# TYPE comlink_request_attempts histogram",
+		`comlink_request_attempts_bucket{endpoint="/metadata",le="1"} 2`,
+		`comlink_request_attempts_sum{endpoint="/metadata"} 2`,
+		`comlink_request_attempts_bucket{endpoint="/player",le="1"} 0`,
+		`comlink_request_attempts_bucket{endpoint="/player",le="2"} 1`,
+		`comlink_request_attempts_sum{endpoint="/player"} 2`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in\n%s", expected, output)
+		}
+	}
+}
+
+func TestPrometheusMetricsInFlight(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{Latency: time.Minute})
+
+	metrics := httpclient.NewPrometheusMetrics()
+
+	comlink := fakeComlink(t, server, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.Metrics = metrics
+	})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _ = comlink.MetadataCtx(ctx, ComlinkGo.RequestBody{})
+	}()
+
+	for server.Requests("/metadata") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if output := scrape(t, metrics); !strings.Contains(output, `comlink_requests_in_flight{endpoint="/metadata"} 1`) {
+		t.Errorf("expected 1 request in flight got\n%s", output)
+	}
+
+	cancel()
+	<-done
+
+	if output := scrape(t, metrics); !strings.Contains(output, `comlink_requests_in_flight{endpoint="/metadata"} 0`) {
+		t.Errorf("expected no request in flight got\n%s", output)
+	}
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+)
+
+var AllyCode = flag.String("allycode", "813479227", "A valid ally code for testing")
+
+func TestPlayerTyped(t *testing.T) {
+	settings := &ComlinkGo.ComlinkSettings{ComlinkURL: *ComlinkURL}
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	payload := ComlinkGo.RequestBody{
+		Payload: ComlinkGo.Payload{
+			AllyCode: *AllyCode,
+		},
+	}
+
+	player, err := comlink.PlayerTyped(payload)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if player.AllyCode != *AllyCode {
+		t.Errorf("expected allyCode %s got %s", *AllyCode, player.AllyCode)
+	}
+
+	if len(player.RosterUnit) == 0 {
+		t.Error("rosterUnit was empty")
+	}
+
+	arena, err := comlink.PlayerArenaTyped(payload)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if arena.PlayerId != player.PlayerId {
+		t.Errorf("expected playerId %s got %s", player.PlayerId, arena.PlayerId)
+	}
+}
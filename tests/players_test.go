@@ -0,0 +1,29 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+)
+
+func TestPlayers(t *testing.T) {
+	settings := &ComlinkGo.ComlinkSettings{ComlinkURL: *ComlinkURL}
+	comlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ids := []string{*AllyCode, "not-a-real-player"}
+
+	players, errs := ComlinkGo.CollectPlayers(comlink.Players(context.Background(), ids, &ComlinkGo.PlayersOptions{Workers: 2}))
+
+	if _, ok := players[*AllyCode]; !ok {
+		t.Errorf("expected %s to be fetched, errors: %v", *AllyCode, errs)
+	}
+
+	if _, ok := errs["not-a-real-player"]; !ok {
+		t.Error("expected an error for not-a-real-player")
+	}
+}
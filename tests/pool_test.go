@@ -0,0 +1,415 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+func newTestPool(t *testing.T, strategy ComlinkGo.PoolStrategy, servers ...*comlinktest.Server) *ComlinkGo.ComlinkPool {
+	t.Helper()
+
+	return newTestPoolWithSettings(t, &ComlinkGo.ComlinkSettings{RetryPolicy: testRetryPolicy()}, strategy, servers...)
+}
+
+func newTestPoolWithSettings(t *testing.T, settings *ComlinkGo.ComlinkSettings, strategy ComlinkGo.PoolStrategy, servers ...*comlinktest.Server) *ComlinkGo.ComlinkPool {
+	t.Helper()
+
+	poolSettings := ComlinkGo.PoolSettings{Strategy: strategy, EjectAfter: 1, EjectFor: time.Minute}
+
+	for _, server := range servers {
+		poolSettings.Nodes = append(poolSettings.Nodes, ComlinkGo.PoolNode{
+			ComlinkURL: server.URL,
+			HMAC:       ComlinkGo.HMACSettings{AccessKey: server.AccessKey, SecretKey: server.SecretKey},
+		})
+	}
+
+	pool, err := ComlinkGo.NewComlinkPool(settings, poolSettings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func TestPoolRoundRobinWithPerNodeHMAC(t *testing.T) {
+	first := comlinktest.NewServer(comlinktest.WithHMAC("first", "secret1"))
+	defer first.Close()
+
+	second := comlinktest.NewServer(comlinktest.WithHMAC("second", "secret2"))
+	defer second.Close()
+
+	pool := newTestPool(t, ComlinkGo.RoundRobin, first, second)
+
+	for range 4 {
+		_, err := pool.Metadata(ComlinkGo.RequestBody{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if first.Requests("/metadata") != 2 || second.Requests("/metadata") != 2 {
+		t.Errorf("expected 2 requests per node got %d and %d", first.Requests("/metadata"), second.Requests("/metadata"))
+	}
+}
+
+func TestPoolLeastInFlight(t *testing.T) {
+	idle := comlinktest.NewServer()
+	defer idle.Close()
+
+	busy := comlinktest.NewServer()
+	defer busy.Close()
+
+	busier := comlinktest.NewServer()
+	defer busier.Close()
+
+	busy.SetFailure("/metadata", comlinktest.Failure{Latency: time.Minute})
+	busier.SetFailure("/metadata", comlinktest.Failure{Latency: time.Minute})
+
+	pool := newTestPool(t, ComlinkGo.LeastInFlight, idle, busy, busier)
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	var wg sync.WaitGroup
+
+	defer wg.Wait()
+	defer cancel()
+
+	_, err := pool.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Leave one request hanging on each of the slow nodes
+	for node := 1; node <= 2; node++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = pool.MetadataCtx(ctx, ComlinkGo.RequestBody{})
+		}()
+
+		waitForInFlight(t, pool, node, 1)
+	}
+
+	// The round robin position now points at the first busy node, the idle one should still win
+	for range 2 {
+		_, err = pool.Metadata(ComlinkGo.RequestBody{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if idle.Requests("/metadata") != 3 {
+		t.Errorf("expected every call to go to the idle node got %d", idle.Requests("/metadata"))
+	}
+}
+
+func waitForInFlight(t *testing.T, pool *ComlinkGo.ComlinkPool, node, inFlight int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for pool.Nodes()[node].InFlight != inFlight {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected node %d to have %d requests in flight got %+v", node, inFlight, pool.Nodes())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPoolFailover(t *testing.T) {
+	broken := comlinktest.NewServer()
+	defer broken.Close()
+
+	healthy := comlinktest.NewServer()
+	defer healthy.Close()
+
+	broken.SetFailure("/player", comlinktest.Failure{StatusCode: http.StatusServiceUnavailable, Body: "down"})
+
+	pool := newTestPool(t, ComlinkGo.LeastInFlight, broken, healthy)
+
+	for range 3 {
+		_, err := pool.Player(ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{AllyCode: *AllyCode}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if broken.Requests("/player") != 1 {
+		t.Errorf("expected the broken node to be ejected after 1 request got %d", broken.Requests("/player"))
+	}
+
+	if healthy.Requests("/player") != 3 {
+		t.Errorf("expected every call to end on the healthy node got %d", healthy.Requests("/player"))
+	}
+
+	if nodes := pool.Nodes(); nodes[0].Healthy || !nodes[1].Healthy {
+		t.Errorf("expected only the broken node to be ejected got %+v", nodes)
+	}
+}
+
+func TestPoolFailoverWithDefaultRetryPolicy(t *testing.T) {
+	broken := comlinktest.NewServer()
+	defer broken.Close()
+
+	healthy := comlinktest.NewServer()
+	defer healthy.Close()
+
+	broken.SetFailure("/player", comlinktest.Failure{StatusCode: http.StatusServiceUnavailable, Body: "down"})
+
+	// The default policy waits 2s before a retry and does not retry a 503 on its own
+	pool := newTestPoolWithSettings(t, &ComlinkGo.ComlinkSettings{}, ComlinkGo.RoundRobin, broken, healthy)
+
+	start := time.Now()
+
+	_, err := pool.Player(ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{AllyCode: *AllyCode}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the retry on the healthy node to skip the delay but it took %s", elapsed)
+	}
+
+	if broken.Requests("/player") != 1 || healthy.Requests("/player") != 1 {
+		t.Errorf("expected 1 request per node got %d and %d", broken.Requests("/player"), healthy.Requests("/player"))
+	}
+}
+
+func TestPoolHealthCheck(t *testing.T) {
+	first := comlinktest.NewServer()
+	defer first.Close()
+
+	second := comlinktest.NewServer()
+	defer second.Close()
+
+	second.SetFailure("/metadata", comlinktest.Failure{DropConnection: true})
+
+	pool := newTestPool(t, ComlinkGo.RoundRobin, first, second)
+
+	pool.CheckHealth(t.Context())
+
+	if nodes := pool.Nodes(); !nodes[0].Healthy || nodes[1].Healthy {
+		t.Fatalf("expected the second node to be ejected got %+v", nodes)
+	}
+
+	second.ClearFailures()
+	pool.CheckHealth(t.Context())
+
+	if nodes := pool.Nodes(); !nodes[0].Healthy || !nodes[1].Healthy {
+		t.Errorf("expected both nodes to be healthy got %+v", nodes)
+	}
+}
+
+func TestPoolIgnoresRateLimiterErrors(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	settings := &ComlinkGo.ComlinkSettings{
+		RetryPolicy: &httpclient.RetryPolicy{MaxAttempts: 1},
+		RateLimit:   &httpclient.RateLimitSettings{Global: httpclient.RateLimit{RequestsPerSecond: 0.001}},
+	}
+
+	pool := newTestPoolWithSettings(t, settings, ComlinkGo.RoundRobin, server)
+
+	_, err := pool.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The limiter has no token left, so this call times out before anything is sent
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = pool.MetadataCtx(ctx, ComlinkGo.RequestBody{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded got %v", err)
+	}
+
+	if nodes := pool.Nodes(); nodes[0].Failures != 0 || !nodes[0].Healthy || nodes[0].InFlight != 0 {
+		t.Errorf("expected the limiter timeout not to count against the node got %+v", nodes)
+	}
+}
+
+func TestPoolKeepsNodeBusyUntilBodyIsClosed(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	pool := newTestPool(t, ComlinkGo.LeastInFlight, server)
+
+	resp, err := pool.MetadataRaw(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if inFlight := pool.Nodes()[0].InFlight; inFlight != 1 {
+		t.Errorf("expected the node to be busy while the body is open got %d in flight", inFlight)
+	}
+
+	resp.Body.Close()
+
+	if inFlight := pool.Nodes()[0].InFlight; inFlight != 0 {
+		t.Errorf("expected closing the body to free the node got %d in flight", inFlight)
+	}
+}
+
+func TestPoolAttemptInterceptorsSeeTheNode(t *testing.T) {
+	first := comlinktest.NewServer(comlinktest.WithHMAC("first", "secret1"))
+	defer first.Close()
+
+	second := comlinktest.NewServer(comlinktest.WithHMAC("second", "secret2"))
+	defer second.Close()
+
+	var seen []string
+
+	settings := &ComlinkGo.ComlinkSettings{
+		RetryPolicy: testRetryPolicy(),
+		AttemptInterceptors: []httpclient.Interceptor{func(next httpclient.Doer) httpclient.Doer {
+			return func(req *http.Request) (*http.Response, error) {
+				seen = append(seen, "http://"+req.URL.Host+" "+req.Header.Get("Authorization"))
+
+				return next(req)
+			}
+		}},
+	}
+
+	pool := newTestPoolWithSettings(t, settings, ComlinkGo.RoundRobin, first, second)
+
+	for range 2 {
+		_, err := pool.Metadata(ComlinkGo.RequestBody{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, server := range []*comlinktest.Server{first, second} {
+		prefix := server.URL + " HMAC-SHA256 Credential=" + server.AccessKey + ","
+		if len(seen) != 2 || !strings.HasPrefix(seen[i], prefix) {
+			t.Fatalf("expected attempt %d to be routed and signed for %s got %q", i+1, server.URL, seen)
+		}
+	}
+}
+
+func TestPoolCloseWaitsForHealthCheck(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/metadata", comlinktest.Failure{Latency: time.Minute})
+
+	pool, err := ComlinkGo.NewComlinkPool(&ComlinkGo.ComlinkSettings{}, ComlinkGo.PoolSettings{
+		Nodes:               []ComlinkGo.PoolNode{{ComlinkURL: server.URL}},
+		HealthCheckInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for server.Requests("/metadata") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a background health check to start")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	// The running check is cancelled by Close, which has to wait for it to record the result
+	pool.Close()
+
+	nodes := pool.Nodes()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if after := pool.Nodes(); after[0] != nodes[0] || nodes[0].Failures == 0 {
+		t.Errorf("expected the cancelled check to be recorded before Close returned got %+v then %+v", nodes, after)
+	}
+}
+
+func TestPoolCircuitBreakerPerNode(t *testing.T) {
+	broken := comlinktest.NewServer()
+	defer broken.Close()
+
+	healthy := comlinktest.NewServer()
+	defer healthy.Close()
+
+	broken.SetFailure("/player", comlinktest.Failure{StatusCode: http.StatusServiceUnavailable, Body: "down"})
+
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	settings := &ComlinkGo.ComlinkSettings{
+		RetryPolicy: testRetryPolicy(),
+		CircuitBreaker: &httpclient.CircuitBreakerSettings{
+			FailureThreshold: 1,
+			CoolDown:         time.Minute,
+			Now:              func() time.Time { return now },
+		},
+	}
+
+	pool := newTestPoolWithSettings(t, settings, ComlinkGo.RoundRobin, broken, healthy)
+
+	if pool.HttpClient.CircuitBreaker != nil {
+		t.Fatal("expected the pool to leave the shared breaker unset")
+	}
+
+	// A shared breaker would open on the first 503 and refuse the retry on the healthy node
+	for range 3 {
+		_, err := pool.Player(ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{AllyCode: *AllyCode}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if broken.Requests("/player") != 1 || healthy.Requests("/player") != 3 {
+		t.Errorf("expected 1 request to the broken node and 3 to the healthy one got %d and %d", broken.Requests("/player"), healthy.Requests("/player"))
+	}
+
+	if nodes := pool.Nodes(); nodes[0].Circuit != httpclient.CircuitOpen || nodes[1].Circuit != httpclient.CircuitClosed {
+		t.Errorf("expected only the broken node's breaker to open got %+v", nodes)
+	}
+}
+
+func TestPoolCircuitBreakerAllNodesOpen(t *testing.T) {
+	first := comlinktest.NewServer()
+	defer first.Close()
+
+	second := comlinktest.NewServer()
+	defer second.Close()
+
+	for _, server := range []*comlinktest.Server{first, second} {
+		server.SetFailure("/player", comlinktest.Failure{StatusCode: http.StatusServiceUnavailable, Body: "down"})
+	}
+
+	settings := &ComlinkGo.ComlinkSettings{
+		RetryPolicy: &httpclient.RetryPolicy{
+			MaxAttempts:          5,
+			BaseDelay:            time.Hour,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		},
+		CircuitBreaker: &httpclient.CircuitBreakerSettings{FailureThreshold: 1, CoolDown: time.Minute},
+	}
+
+	pool := newTestPoolWithSettings(t, settings, ComlinkGo.RoundRobin, first, second)
+
+	// With an hour between retries this only returns quickly because every breaker is open
+	_, err := pool.Player(ComlinkGo.RequestBody{Payload: ComlinkGo.Payload{AllyCode: *AllyCode}})
+	if !errors.Is(err, httpclient.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen got %v", err)
+	}
+
+	if first.Requests("/player") != 1 || second.Requests("/player") != 1 {
+		t.Errorf("expected 1 request per node got %d and %d", first.Requests("/player"), second.Requests("/player"))
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := httpclient.NewRateLimiter(httpclient.RateLimitSettings{
+		Endpoints: map[string]httpclient.RateLimit{
+			"/player": {RequestsPerSecond: 20, Burst: 1},
+		},
+	})
+
+	ctx := context.Background()
+	start := time.Now()
+
+	for range 5 {
+		err := limiter.Wait(ctx, "/metadata")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("/metadata should not be limited but took %s", elapsed)
+	}
+
+	start = time.Now()
+
+	for range 5 {
+		err := limiter.Wait(ctx, "/prefix/Player")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("/player should be limited to 20/s but 5 requests took %s", elapsed)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	err := limiter.Wait(canceled, "/player")
+	if err == nil {
+		t.Error("expected an error from a canceled context")
+	}
+}
+
+func TestRateLimiterReturnsEndpointTokenWhenGlobalWaitFails(t *testing.T) {
+	limiter := httpclient.NewRateLimiter(httpclient.RateLimitSettings{
+		Global: httpclient.RateLimit{RequestsPerSecond: 20, Burst: 1},
+		Endpoints: map[string]httpclient.RateLimit{
+			"/player": {RequestsPerSecond: 0.1, Burst: 2},
+		},
+	})
+
+	err := limiter.Wait(context.Background(), "/player")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The endpoint token is free but the global one is not, so this fails in the global wait
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = limiter.Wait(canceled, "/player")
+	if err == nil {
+		t.Fatal("expected an error from a canceled context")
+	}
+
+	// Only the global limit should be waited on now, a lost endpoint token would mean 10 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err = limiter.Wait(ctx, "/player")
+	if err != nil {
+		t.Errorf("expected the endpoint token to be returned got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+)
+
+func TestRequestBodyPointerSendsZeroValues(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	comlink := fakeComlink(t, server)
+
+	payload := ComlinkGo.RequestBodyPointer{
+		Payload: &ComlinkGo.PayloadPointer{
+			FilterType: ComlinkGo.Ptr(ComlinkGo.FilterTypeSearchCriteria),
+			StartIndex: ComlinkGo.Ptr(0),
+			SearchCriteria: &ComlinkGo.SearchCriteriaPointer{
+				IncludeInviteOnly: ComlinkGo.Ptr(false),
+			},
+		},
+		Enums: ComlinkGo.Ptr(false),
+	}
+
+	_, err := comlink.GetGuilds(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent map[string]any
+
+	err = json.Unmarshal(server.LastBody("/getGuilds"), &sent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inner := sent["payload"].(map[string]any)
+
+	if inner["startIndex"] != float64(0) {
+		t.Errorf("expected startIndex 0 on the wire got %v", inner["startIndex"])
+	}
+
+	if criteria := inner["searchCriteria"].(map[string]any); criteria["includeInviteOnly"] != false {
+		t.Errorf("expected includeInviteOnly false on the wire got %v", criteria["includeInviteOnly"])
+	}
+
+	if sent["enums"] != false {
+		t.Errorf("expected enums false on the wire got %v", sent["enums"])
+	}
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := httpclient.RetryPolicy{
+		MaxAttempts:       3,
+		BaseDelay:         time.Second,
+		MaxDelay:          5 * time.Second,
+		RespectRetryAfter: true,
+	}
+
+	if delay := policy.Delay(1, nil); delay != 2*time.Second {
+		t.Errorf("expected 2s got %s", delay)
+	}
+
+	if delay := policy.Delay(10, nil); delay != 5*time.Second {
+		t.Errorf("expected delay to be capped at 5s got %s", delay)
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "3")
+
+	if delay := policy.Delay(0, resp); delay != 3*time.Second {
+		t.Errorf("expected Retry-After of 3s got %s", delay)
+	}
+
+	policy.Jitter = 0.5
+
+	for range 100 {
+		delay := policy.Delay(1, nil)
+		if delay < time.Second || delay > 2*time.Second {
+			t.Fatalf("jittered delay %s out of range", delay)
+		}
+	}
+}
+
+func TestRetryPolicyDelayDoesNotOverflow(t *testing.T) {
+	policy := httpclient.RetryPolicy{BaseDelay: 3 * time.Second}
+
+	previous := policy.Delay(0, nil)
+
+	for attempt := 1; attempt <= 100; attempt++ {
+		delay := policy.Delay(attempt, nil)
+		if delay < previous {
+			t.Fatalf("expected attempt %d to wait at least %s got %s", attempt, previous, delay)
+		}
+
+		previous = delay
+	}
+
+	if previous != time.Duration(math.MaxInt64) {
+		t.Errorf("expected the delay to stop at the largest time.Duration got %s", previous)
+	}
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+)
+
+func signedRequest(t *testing.T, accessKey, secretKey string) *http.Request {
+	t.Helper()
+
+	comlink, err := ComlinkGo.GetComlink(&ComlinkGo.ComlinkSettings{
+		ComlinkURL: "http://localhost:3000",
+		HMAC:       ComlinkGo.HMACSettings{AccessKey: accessKey, SecretKey: secretKey},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := map[string]any{"payload": map[string]any{"allyCode": "123456789"}}
+
+	headers, err := comlink.Sign("/player", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(payload)
+
+	req := httptest.NewRequest(http.MethodPost, "/player", bytes.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	return req
+}
+
+func TestVerifySignature(t *testing.T) {
+	keys := map[string]string{"first": "secret1", "second": "secret2"}
+
+	err := ComlinkGo.VerifySignature(signedRequest(t, "second", "secret2"), keys)
+	if err != nil {
+		t.Errorf("expected a valid signature got %v", err)
+	}
+
+	err = ComlinkGo.VerifySignature(signedRequest(t, "first", "wrong"), keys)
+	if !errors.Is(err, ComlinkGo.ErrSignatureMismatch) {
+		t.Errorf("expected ErrSignatureMismatch got %v", err)
+	}
+
+	err = ComlinkGo.VerifySignature(signedRequest(t, "third", "secret3"), keys)
+	if !errors.Is(err, ComlinkGo.ErrUnknownAccessKey) || strings.Contains(err.Error(), "third") {
+		t.Errorf("expected ErrUnknownAccessKey without the access key got %v", err)
+	}
+
+	err = ComlinkGo.VerifySignature(httptest.NewRequest(http.MethodPost, "/player", nil), keys)
+	if !errors.Is(err, ComlinkGo.ErrMissingSignature) {
+		t.Errorf("expected ErrMissingSignature got %v", err)
+	}
+
+	verifier := ComlinkGo.SignatureVerifier{
+		AccessKeys:   keys,
+		MaxClockSkew: time.Minute,
+		Clock:        ComlinkGo.ClockFunc(func() time.Time { return time.Now().Add(2 * time.Minute) }),
+	}
+
+	err = verifier.Verify(signedRequest(t, "first", "secret1"))
+	if !errors.Is(err, ComlinkGo.ErrClockSkew) {
+		t.Errorf("expected ErrClockSkew got %v", err)
+	}
+}
+
+func TestSignatureMiddleware(t *testing.T) {
+	verifier := ComlinkGo.SignatureVerifier{AccessKeys: map[string]string{"access": "secret"}}
+
+	handler := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("body was not restored: %v", err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, signedRequest(t, "access", "secret"))
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected 200 got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, signedRequest(t, "access", "wrong"))
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 got %d", recorder.Code)
+	}
+
+	verifier.MaxBodySize = 8
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, signedRequest(t, "access", "secret"))
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for a body over MaxBodySize got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	id     byte
+	attrs  map[string]any
+	ended  bool
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type spanKey struct{}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs ...httpclient.Attribute) (context.Context, httpclient.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	span := &recordedSpan{name: name, parent: parent, id: byte(len(r.spans) + 1), attrs: map[string]any{}}
+	r.spans = append(r.spans, span)
+
+	handle := &recordingSpan{tracer: r, span: span}
+	handle.SetAttributes(attrs...)
+
+	return context.WithValue(ctx, spanKey{}, span), handle
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	span   *recordedSpan
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...httpclient.Attribute) {
+	for _, attr := range attrs {
+		s.span.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.span.attrs["error"] = err.Error()
+}
+
+func (s *recordingSpan) End() {
+	s.span.ended = true
+}
+
+func (s *recordingSpan) TraceParent() string {
+	return httpclient.FormatTraceParent([16]byte{1}, [8]byte{s.span.id}, true)
+}
+
+type headerRecordingTransport struct {
+	mu           sync.Mutex
+	traceParents []string
+}
+
+func (h *headerRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.mu.Lock()
+	h.traceParents = append(h.traceParents, req.Header.Get("traceparent"))
+	h.mu.Unlock()
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTracing(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	server.SetFailure("/player", comlinktest.Failure{DropConnection: true, Times: 1})
+
+	tracer := &recordingTracer{}
+	transport := &headerRecordingTransport{}
+
+	comlink := fakeComlink(t, server, func(settings *ComlinkGo.ComlinkSettings) {
+		settings.Tracer = tracer
+		settings.Transport = transport
+		settings.AllyCodeHashKey = []byte("trace-key")
+	})
+
+	_, err := comlink.Send(context.Background(), ComlinkGo.PlayerRequest{AllyCode: "813-479-227"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("expected a call span and two attempt spans got %d", len(tracer.spans))
+	}
+
+	call := tracer.spans[0]
+	mac := hmac.New(sha256.New, []byte("trace-key"))
+	mac.Write([]byte("813479227"))
+	hash := mac.Sum(nil)
+
+	if call.name != "comlink /player" || call.attrs[httpclient.AttributeEndpoint] != "/player" ||
+		call.attrs[httpclient.AttributeStatusCode] != http.StatusOK ||
+		call.attrs[httpclient.AttributeAllyCodeHash] != hex.EncodeToString(hash) {
+		t.Errorf("unexpected call span %+v", call)
+	}
+
+	for i, attempt := range tracer.spans[1:] {
+		if attempt.parent != call || attempt.attrs[httpclient.AttributeAttempt] != i+1 || !attempt.ended {
+			t.Errorf("unexpected attempt span %+v", attempt)
+		}
+	}
+
+	if first := tracer.spans[1]; first.attrs["error"] == nil || first.attrs[httpclient.AttributeStatusCode] != nil {
+		t.Errorf("expected the dropped attempt to record an error and no status got %+v", first.attrs)
+	}
+
+	if second := tracer.spans[2]; second.attrs[httpclient.AttributeStatusCode] != http.StatusOK || second.attrs["error"] != nil {
+		t.Errorf("expected the second attempt to record 200 got %+v", second.attrs)
+	}
+
+	expected := []string{
+		"00-01000000000000000000000000000000-0200000000000000-01",
+		"00-01000000000000000000000000000000-0300000000000000-01",
+	}
+	if !slices.Equal(transport.traceParents, expected) {
+		t.Errorf("expected traceparent %v got %v", expected, transport.traceParents)
+	}
+}
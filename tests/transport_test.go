@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Lego-Fan9/ComlinkGo"
+	"github.com/Lego-Fan9/ComlinkGo/comlinktest"
+	"github.com/Lego-Fan9/ComlinkGo/httpclient"
+)
+
+type countingTransport struct {
+	count atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.count.Add(1)
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCustomTransport(t *testing.T) {
+	server := comlinktest.NewServer()
+	defer server.Close()
+
+	first, second := &countingTransport{}, &countingTransport{}
+
+	settings := server.Settings()
+	settings.Transport = first
+
+	firstComlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings = server.Settings()
+	settings.HTTPClient = &http.Client{Transport: second}
+
+	secondComlink, err := ComlinkGo.GetComlink(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = firstComlink.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = secondComlink.Metadata(ComlinkGo.RequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.count.Load() != 1 || second.count.Load() != 1 {
+		t.Errorf("expected one request per transport got %d and %d", first.count.Load(), second.count.Load())
+	}
+
+	if httpclient.Client != nil {
+		t.Error("GetComlink should not set the httpclient.Client global")
+	}
+}